@@ -0,0 +1,485 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"wakeclaude/internal/app"
+	"wakeclaude/internal/scheduler"
+	"wakeclaude/internal/tui"
+)
+
+var permissionModes = []string{"acceptEdits", "plan", "bypassPermissions", "default"}
+
+type addOptions struct {
+	projectsRoot string
+	project      string
+	prompt       string
+	preCommand   string
+	postCommand  string
+	env          envFlag
+	description  string
+	tags         string
+	group        string
+	notify       string
+	notifySound  string
+	once         bool
+	daily        bool
+	weekly       bool
+	monthly      bool
+	day          int
+	lastDay      bool
+	atLogin      bool
+	cron         string
+	every        string
+	date         string
+	clock        string
+	weekday      string
+	model        string
+	permission   string
+	newSession   bool
+	resume       string
+	fork         bool
+	home         string
+	claudeBin    string
+	minBattery   int
+	requireAC    bool
+	network      bool
+	networkHost  string
+	jsonOutput   bool
+	lowPriority  bool
+	start        string
+	processType  string
+	ifDirty      bool
+	retries      int
+	retryDelay   string
+}
+
+func runAdd(args []string) int {
+	fs := flag.NewFlagSet("wakeclaude add", flag.ContinueOnError)
+	fs.SetOutput(os.Stderr)
+
+	var opts addOptions
+	fs.StringVar(&opts.projectsRoot, "projects-root", "", "Root directory for Claude projects (default: ~/.claude/projects)")
+	fs.StringVar(&opts.project, "project", "", "Project directory to run in")
+	fs.StringVar(&opts.prompt, "prompt", "", "Prompt to send to claude")
+	fs.StringVar(&opts.preCommand, "pre-command", "", "Shell command to run in the project before the prompt; the run is aborted if it fails")
+	fs.StringVar(&opts.postCommand, "post-command", "", "Shell command to run in the project after the prompt, even if claude fails")
+	fs.Var(&opts.env, "env", "Environment variable for claude as KEY=VALUE (repeatable)")
+	fs.StringVar(&opts.description, "description", "", "Short description shown as the notification title")
+	fs.StringVar(&opts.tags, "tags", "", "Comma-separated tags for filtering (e.g. work,reports)")
+	fs.StringVar(&opts.group, "group", "", "Group name for pausing or deleting related schedules together")
+	fs.StringVar(&opts.notify, "notify", "always", "When to show a notification (always, failure, never)")
+	fs.StringVar(&opts.notifySound, "notify-sound", "", "Notification sound name, or none (default: Glass on success, Basso on failure)")
+	fs.BoolVar(&opts.once, "once", false, "Run once at --date and --time")
+	fs.BoolVar(&opts.daily, "daily", false, "Run every day at --time")
+	fs.BoolVar(&opts.weekly, "weekly", false, "Run every week on --weekday at --time")
+	fs.BoolVar(&opts.monthly, "monthly", false, "Run every month on --day at --time")
+	fs.IntVar(&opts.day, "day", 0, "Day of month for --monthly (1-31; shorter months use their last day)")
+	fs.BoolVar(&opts.lastDay, "last-day", false, "Run --monthly on the last day of each month")
+	fs.BoolVar(&opts.atLogin, "at-login", false, "Run each time you log in (no --time, no sudo)")
+	fs.StringVar(&opts.every, "every", "", "Run every interval from now, e.g. 4h or 90m (no --time)")
+	fs.StringVar(&opts.cron, "cron", "", "Run on a 5-field cron expression, e.g. \"0 9 * * 1-5\" (no --time)")
+	fs.StringVar(&opts.date, "date", "", "Date for --once (YYYY-MM-DD)")
+	fs.StringVar(&opts.start, "start", "", "Don't start a recurring schedule before this date (YYYY-MM-DD)")
+	fs.StringVar(&opts.clock, "time", "", "Time of day (HH:MM, 24-hour; comma-separated for --daily or --weekly)")
+	fs.StringVar(&opts.weekday, "weekday", "", "Day of week for --weekly (e.g. monday)")
+	fs.StringVar(&opts.model, "model", "", "Claude model (auto, opus, sonnet, haiku, a full claude-... id, or any name claude accepts; default: config defaultModel, else auto)")
+	fs.StringVar(&opts.permission, "permission", "", "Permission mode (acceptEdits, plan, bypassPermissions, or default for no --permission-mode; default: config defaultPermissionMode, else acceptEdits)")
+	fs.BoolVar(&opts.newSession, "new-session", false, "Start a new session on every run (default)")
+	fs.StringVar(&opts.resume, "resume", "", "Resume an existing session by id")
+	fs.BoolVar(&opts.fork, "fork", false, "Fork the resumed session instead of continuing it")
+	fs.IntVar(&opts.minBattery, "min-battery", 0, "Skip the run when on battery below this percent")
+	fs.BoolVar(&opts.requireAC, "require-ac", false, "Skip the run unless on AC power")
+	fs.BoolVar(&opts.network, "require-network", false, "Skip the run when offline")
+	fs.StringVar(&opts.networkHost, "network-host", scheduler.DefaultNetworkHost, "host:port to check for --require-network")
+	fs.BoolVar(&opts.ifDirty, "if-dirty", false, "Skip the run unless the project has uncommitted git changes")
+	fs.IntVar(&opts.retries, "retries", 0, fmt.Sprintf("Retry a failed run up to this many times (max %d)", scheduler.MaxRetries))
+	fs.StringVar(&opts.retryDelay, "retry-delay", "", "Wait before the first retry, doubling after each (default 1m)")
+	fs.BoolVar(&opts.jsonOutput, "output-json", false, "Run claude with --output-format json and keep its result text in the log")
+	fs.BoolVar(&opts.lowPriority, "low-priority", false, "Run at background priority so it yields to foreground work")
+	fs.StringVar(&opts.processType, "process-type", "", "launchd ProcessType: interactive finishes sooner but competes with your apps, background is throttled the most (default: standard)")
+	fs.BoolVar(&explainSudo, "explain-sudo", false, "List the commands that need sudo and ask before the password prompt")
+	fs.StringVar(&opts.home, "home", "", "Run claude with this HOME (for a separate ~/.claude)")
+	fs.StringVar(&opts.claudeBin, "claude-bin", "", "Run this claude binary instead of the one found in PATH")
+	var skipVerify bool
+	fs.BoolVar(&skipVerify, "skip-verify", false, "Don't check the setup token with claude before saving the schedule")
+	var asJSON bool
+	fs.BoolVar(&asJSON, "json", false, "Print the new schedule as json instead of a summary")
+
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return 0
+		}
+		return 2
+	}
+	if fs.NArg() > 0 {
+		fmt.Fprintln(os.Stderr, "wakeclaude add does not accept positional arguments.")
+		fs.Usage()
+		return 2
+	}
+
+	draft, err := buildAddDraft(opts)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+	if app.LooksLikeShellCommand(draft.Prompt) {
+		fmt.Fprintln(os.Stderr, "Note: the prompt looks like a shell command; it will be sent to Claude as a prompt, not run in a shell.")
+	}
+
+	if !app.ClaudeAvailable() {
+		fmt.Fprintf(os.Stderr, "claude not found in PATH; install: %s\n", app.ClaudeInstallCmd)
+		return 1
+	}
+
+	release := holdStdout(asJSON)
+	defer release()
+	store, err := scheduler.DefaultStore()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	entry, err := buildEntry(draft, nil)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	if err := ensureTokenPresent(entry, !skipVerify); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	if err := createSchedule(store, entry); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	if asJSON {
+		release()
+		return printJSON(entry)
+	}
+	printScheduled(entry)
+	return 0
+}
+
+func buildAddDraft(opts addOptions) (*tui.Draft, error) {
+	if strings.TrimSpace(opts.project) == "" {
+		return nil, fmt.Errorf("--project is required")
+	}
+	if strings.TrimSpace(opts.prompt) == "" {
+		return nil, fmt.Errorf("--prompt is required")
+	}
+
+	schedule, err := buildAddSchedule(opts)
+	if err != nil {
+		return nil, err
+	}
+	if start := strings.TrimSpace(opts.start); start != "" {
+		if schedule.Type == "once" {
+			return nil, fmt.Errorf("--start is for recurring schedules; --once runs at --date")
+		}
+		at, err := scheduler.ParseStartDate(start, schedule.Timezone)
+		if err != nil {
+			return nil, err
+		}
+		if at.AddDate(0, 0, 1).Before(time.Now()) {
+			return nil, fmt.Errorf("--start is in the past: %s", start)
+		}
+		schedule.StartDate = start
+	}
+
+	cfg, err := app.LoadConfig()
+	if err != nil {
+		return nil, err
+	}
+	modelValue := opts.model
+	if strings.TrimSpace(modelValue) == "" {
+		modelValue = cfg.DefaultModel
+	}
+	// Like the tui's custom model, any name is kept; buildEntry warns about
+	// one it doesn't recognize.
+	model, _ := scheduler.NormalizeModel(modelValue)
+	perm := strings.TrimSpace(opts.permission)
+	if perm == "" {
+		perm = cfg.DefaultPermissionMode
+	}
+	if perm == "" {
+		perm = "acceptEdits"
+	}
+	if !knownPermissionMode(perm) {
+		return nil, fmt.Errorf("unknown permission mode: %s (use %s)", perm, strings.Join(permissionModes, ", "))
+	}
+
+	notify := strings.ToLower(strings.TrimSpace(opts.notify))
+	if !scheduler.ValidNotifyMode(notify) {
+		return nil, fmt.Errorf("unknown notify setting: %s (use %s)", notify, strings.Join(scheduler.NotifyModes, ", "))
+	}
+
+	if opts.minBattery < 0 || opts.minBattery > 100 {
+		return nil, fmt.Errorf("--min-battery must be between 0 and 100")
+	}
+	if opts.retries < 0 || opts.retries > scheduler.MaxRetries {
+		return nil, fmt.Errorf("--retries must be between 0 and %d", scheduler.MaxRetries)
+	}
+	if opts.retryDelay != "" {
+		if opts.retries == 0 {
+			return nil, fmt.Errorf("--retry-delay requires --retries")
+		}
+		if _, err := scheduler.ParseRetryDelay(opts.retryDelay); err != nil {
+			return nil, err
+		}
+	}
+	processType := strings.ToLower(strings.TrimSpace(opts.processType))
+	if processType != "" && !scheduler.ValidProcessType(processType) {
+		return nil, fmt.Errorf("unknown process type: %s (use %s)", processType, strings.Join(scheduler.ProcessTypes, ", "))
+	}
+	if opts.lowPriority && processType != "" && processType != "background" {
+		return nil, fmt.Errorf("--low-priority runs as background; it can't be combined with --process-type %s", processType)
+	}
+	networkHost := ""
+	if opts.network {
+		networkHost = strings.TrimSpace(opts.networkHost)
+		if _, _, err := net.SplitHostPort(networkHost); err != nil {
+			return nil, fmt.Errorf("invalid --network-host: %s (use host:port)", networkHost)
+		}
+	}
+
+	env, err := scheduler.ParseEnv(strings.Join(opts.env, "\n"))
+	if err != nil {
+		return nil, fmt.Errorf("--env: %w", err)
+	}
+
+	if opts.newSession && opts.resume != "" {
+		return nil, fmt.Errorf("use either --new-session or --resume, not both")
+	}
+	if opts.fork && opts.resume == "" {
+		return nil, fmt.Errorf("--fork requires --resume")
+	}
+
+	root := opts.projectsRoot
+	if root == "" && strings.TrimSpace(opts.home) != "" {
+		home, err := app.ExpandHome(strings.TrimSpace(opts.home))
+		if err != nil {
+			return nil, err
+		}
+		root = filepath.Join(home, ".claude", "projects")
+	}
+	project, err := findAddProject(root, opts.project)
+	if err != nil {
+		// A directory Claude hasn't seen yet still works for new sessions.
+		dir, dirErr := app.NormalizePath(opts.project)
+		if opts.resume != "" || dirErr != nil || !scheduler.IsValidWorkDir(dir) {
+			return nil, err
+		}
+		project = app.Project{Path: dir, CWD: dir}
+	}
+	projectPath := project.CWD
+	if projectPath == "" {
+		projectPath = project.Path
+	}
+
+	draft := &tui.Draft{
+		ProjectPath:  projectPath,
+		Model:        model,
+		Permission:   perm,
+		Prompt:       opts.prompt,
+		PreCommand:   opts.preCommand,
+		PostCommand:  opts.postCommand,
+		Env:          env,
+		Description:  opts.description,
+		Tags:         scheduler.ParseTags(opts.tags),
+		Group:        opts.group,
+		Notify:       notify,
+		NotifySound:  strings.TrimSpace(opts.notifySound),
+		HomeDir:      opts.home,
+		ClaudeBinary: opts.claudeBin,
+		MinBattery:   opts.minBattery,
+		RequireAC:    opts.requireAC,
+		NetworkHost:  networkHost,
+		RequireDirty: opts.ifDirty,
+		Retries:      opts.retries,
+		RetryDelay:   strings.TrimSpace(opts.retryDelay),
+		JSONOutput:   opts.jsonOutput,
+		LowPriority:  opts.lowPriority,
+		ProcessType:  processType,
+		Schedule:     schedule,
+	}
+	if opts.resume == "" {
+		draft.NewSession = true
+		return draft, nil
+	}
+
+	session, err := findAddSession(project, opts.resume)
+	if err != nil {
+		return nil, err
+	}
+	draft.SessionID = session.ID
+	draft.SessionPath = session.Path
+	draft.ForkSession = opts.fork
+	return draft, nil
+}
+
+func buildAddSchedule(opts addOptions) (tui.Schedule, error) {
+	selected := 0
+	for _, set := range []bool{opts.once, opts.daily, opts.weekly, opts.monthly, opts.atLogin, opts.cron != "", opts.every != ""} {
+		if set {
+			selected++
+		}
+	}
+	if selected != 1 {
+		return tui.Schedule{}, fmt.Errorf("choose exactly one of --once, --daily, --weekly, --monthly, --every, --cron, or --at-login")
+	}
+	if opts.every != "" {
+		if strings.TrimSpace(opts.clock) != "" {
+			return tui.Schedule{}, fmt.Errorf("--every takes no --time")
+		}
+		minutes, err := scheduler.ParseInterval(opts.every)
+		if err != nil {
+			return tui.Schedule{}, fmt.Errorf("--every: %w", err)
+		}
+		return tui.Schedule{Type: "interval", Interval: minutes, Timezone: app.ScheduleTimezone()}, nil
+	}
+	if opts.cron != "" {
+		if strings.TrimSpace(opts.clock) != "" {
+			return tui.Schedule{}, fmt.Errorf("--cron takes no --time")
+		}
+		expr := strings.Join(strings.Fields(opts.cron), " ")
+		if err := scheduler.ValidateCron(expr); err != nil {
+			return tui.Schedule{}, fmt.Errorf("--cron: %w", err)
+		}
+		return tui.Schedule{Type: "cron", Cron: expr, Timezone: app.ScheduleTimezone()}, nil
+	}
+	if opts.atLogin {
+		if strings.TrimSpace(opts.clock) != "" {
+			return tui.Schedule{}, fmt.Errorf("--at-login takes no --time")
+		}
+		return tui.Schedule{Type: "login", Timezone: app.ScheduleTimezone()}, nil
+	}
+	if strings.TrimSpace(opts.clock) == "" {
+		return tui.Schedule{}, fmt.Errorf("--time is required")
+	}
+	times, err := scheduler.ParseTimes(opts.clock)
+	if err != nil {
+		return tui.Schedule{}, fmt.Errorf("--time: %w", err)
+	}
+
+	schedule := tui.Schedule{
+		Time:     times[0],
+		Timezone: app.ScheduleTimezone(),
+	}
+	switch {
+	case opts.once:
+		if len(times) > 1 {
+			return tui.Schedule{}, fmt.Errorf("--once takes a single --time")
+		}
+		if _, err := time.Parse("2006-01-02", strings.TrimSpace(opts.date)); err != nil {
+			return tui.Schedule{}, fmt.Errorf("--once requires --date as YYYY-MM-DD")
+		}
+		schedule.Type = "once"
+		schedule.Date = strings.TrimSpace(opts.date)
+	case opts.daily:
+		schedule.Type = "daily"
+		if len(times) > 1 {
+			schedule.Times = times
+		}
+	case opts.weekly:
+		if strings.TrimSpace(opts.weekday) == "" {
+			return tui.Schedule{}, fmt.Errorf("--weekly requires --weekday")
+		}
+		if _, ok := scheduler.WeekdayNumber(opts.weekday); !ok {
+			return tui.Schedule{}, fmt.Errorf("invalid weekday: %s", opts.weekday)
+		}
+		schedule.Type = "weekly"
+		schedule.Weekday = weekdayLabel(opts.weekday)
+		if len(times) > 1 {
+			schedule.Times = times
+		}
+	case opts.monthly:
+		if len(times) > 1 {
+			return tui.Schedule{}, fmt.Errorf("--monthly takes a single --time")
+		}
+		if opts.lastDay {
+			if opts.day != 0 {
+				return tui.Schedule{}, fmt.Errorf("use either --day or --last-day")
+			}
+			opts.day = scheduler.LastDayOfMonth
+		} else if opts.day < 1 || opts.day > 31 {
+			return tui.Schedule{}, fmt.Errorf("--monthly requires --day between 1 and 31 (or --last-day)")
+		}
+		schedule.Type = "monthly"
+		schedule.Day = opts.day
+	}
+	return schedule, nil
+}
+
+func findAddProject(root, path string) (app.Project, error) {
+	wanted, err := app.NormalizePath(path)
+	if err != nil {
+		return app.Project{}, err
+	}
+	projects, err := app.DiscoverProjects(root)
+	if err != nil {
+		return app.Project{}, err
+	}
+	for _, project := range projects {
+		if project.CWD != "" && filepath.Clean(project.CWD) == wanted {
+			return project, nil
+		}
+		if filepath.Clean(project.Path) == wanted {
+			return project, nil
+		}
+	}
+	for _, project := range projects {
+		if project.CWD != "" && app.SamePath(project.CWD, wanted) {
+			return project, nil
+		}
+	}
+	return app.Project{}, fmt.Errorf("project not found: %s; run Claude in it once first", app.HumanizePath(wanted))
+}
+
+func findAddSession(project app.Project, id string) (app.Session, error) {
+	sessions, err := app.CollectSessions(project.Path)
+	if err != nil {
+		return app.Session{}, err
+	}
+	for _, session := range sessions {
+		if session.ID == id {
+			return session, nil
+		}
+	}
+	return app.Session{}, fmt.Errorf("session not found in %s: %s", project.DisplayName, id)
+}
+
+func knownPermissionMode(value string) bool {
+	for _, mode := range permissionModes {
+		if mode == value {
+			return true
+		}
+	}
+	return false
+}
+
+func weekdayLabel(name string) string {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if name == "" {
+		return ""
+	}
+	return strings.ToUpper(name[:1]) + name[1:]
+}
+
+// envFlag collects each --env KEY=VALUE.
+type envFlag []string
+
+func (f *envFlag) String() string {
+	return strings.Join(*f, ",")
+}
+
+func (f *envFlag) Set(value string) error {
+	*f = append(*f, value)
+	return nil
+}
@@ -0,0 +1,112 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"wakeclaude/internal/scheduler"
+)
+
+func exportBackup(store *scheduler.Store, path string) int {
+	if path == "-" {
+		if err := store.ExportSchedules(os.Stdout); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+		return 0
+	}
+	file, err := os.Create(path)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "create %s: %v\n", path, err)
+		return 1
+	}
+	if err := store.ExportSchedules(file); err != nil {
+		file.Close()
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	if err := file.Close(); err != nil {
+		fmt.Fprintf(os.Stderr, "write %s: %v\n", path, err)
+		return 1
+	}
+	fmt.Fprintf(os.Stderr, "Exported schedules to %s\n", path)
+	return 0
+}
+
+// importBackup schedules the backed-up entries that aren't scheduled yet. The
+// binary path, user, home and PATH come from this machine, like a restore
+// from the trash.
+func importBackup(store *scheduler.Store, path string) int {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "read %s: %v\n", path, err)
+		return 1
+	}
+	backup, err := scheduler.ParseSchedules(data)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%s: %v\n", path, err)
+		return 1
+	}
+	schedules, err := store.LoadSchedules()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	seen := make(map[string]bool)
+	for _, entry := range schedules {
+		seen[entry.ID] = true
+	}
+	var entries []scheduler.ScheduleEntry
+	var commands []string
+	failed := 0
+	for i := range backup {
+		old := backup[i]
+		if old.ID == "" {
+			fmt.Fprintln(os.Stderr, "skipped a schedule without an id")
+			failed++
+			continue
+		}
+		if seen[old.ID] {
+			fmt.Printf("%s  already scheduled; skipped\n", old.ID)
+			continue
+		}
+		seen[old.ID] = true
+		entry, err := buildEntry(draftFromEntry(old), &old)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%s  %s: %v\n", old.ID, scheduler.ScheduleLabel(old), err)
+			failed++
+			continue
+		}
+		entries = append(entries, entry)
+		commands = append(commands, scheduler.InstallCommands(entry)...)
+	}
+	if len(entries) == 0 {
+		fmt.Println("Nothing to import.")
+		if failed > 0 {
+			return 1
+		}
+		return 0
+	}
+
+	if err := ensureSudoFor(commands); err != nil {
+		fmt.Fprintln(os.Stderr, sudoFailure(err, "sudo required to schedule wakeclaude"))
+		return 1
+	}
+	imported := 0
+	for _, entry := range entries {
+		if err := installSchedule(store, entry); err != nil {
+			fmt.Fprintf(os.Stderr, "%s: %v\n", entry.ID, err)
+			failed++
+			continue
+		}
+		fmt.Printf("%s  %s (next: %s)\n", entry.ID, scheduler.ScheduleLabel(entry), nextRunLabel(entry))
+		imported++
+	}
+	fmt.Printf("Imported %d schedule(s).\n", imported)
+	if failed > 0 {
+		fmt.Fprintf(os.Stderr, "%d schedule(s) could not be imported.\n", failed)
+		return 1
+	}
+	return 0
+}
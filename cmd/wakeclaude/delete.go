@@ -0,0 +1,86 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"wakeclaude/internal/scheduler"
+)
+
+// deleteByID removes the comma-separated schedules without the tui. If one
+// fails, the ones already removed are scheduled again.
+func deleteByID(store *scheduler.Store, ids string, asJSON bool) int {
+	release := holdStdout(asJSON)
+	defer release()
+	schedules, err := store.LoadSchedules()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	var targets []scheduler.ScheduleEntry
+	seen := make(map[string]bool)
+	for _, id := range strings.Split(ids, ",") {
+		id = strings.TrimSpace(id)
+		if id == "" || seen[id] {
+			continue
+		}
+		seen[id] = true
+		entry, ok := findSchedule(schedules, id)
+		if !ok {
+			fmt.Fprintf(os.Stderr, "schedule not found: %s\n", id)
+			return 1
+		}
+		targets = append(targets, entry)
+	}
+	if len(targets) == 0 {
+		fmt.Fprintln(os.Stderr, "schedule not found")
+		return 1
+	}
+
+	var commands []string
+	for _, entry := range targets {
+		commands = append(commands, scheduler.RemoveCommands(entry)...)
+	}
+	if err := ensureSudoFor(commands); err != nil {
+		fmt.Fprintln(os.Stderr, sudoFailure(err, "sudo required to delete wakeclaude schedule"))
+		return 1
+	}
+	for i, entry := range targets {
+		if err := deleteSchedule(store, entry); err != nil {
+			fmt.Fprintf(os.Stderr, "%s: %v\n", entry.ID, err)
+			rollbackDeletes(store, targets[:i])
+			return 1
+		}
+	}
+	if asJSON {
+		deleted := make([]string, 0, len(targets))
+		for _, entry := range targets {
+			deleted = append(deleted, entry.ID)
+		}
+		release()
+		return printJSON(struct {
+			Deleted []string `json:"deleted"`
+		}{deleted})
+	}
+	for _, entry := range targets {
+		printDeleted(entry)
+	}
+	return 0
+}
+
+func rollbackDeletes(store *scheduler.Store, deleted []scheduler.ScheduleEntry) {
+	now := time.Now()
+	for _, entry := range deleted {
+		if err := createSchedule(store, entry); err != nil {
+			fmt.Fprintf(os.Stderr, "warning: could not put back %s: %v (it is still in the trash)\n", entry.ID, err)
+			continue
+		}
+		_ = store.RemoveFromTrash(entry.ID, now)
+	}
+	if len(deleted) > 0 {
+		fmt.Fprintf(os.Stderr, "Put back %d schedule(s) deleted before the failure.\n", len(deleted))
+	}
+}
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"wakeclaude/internal/app"
+	"wakeclaude/internal/scheduler"
+)
+
+func dryRun(store *scheduler.Store, id string) int {
+	schedules, err := store.LoadSchedules()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	var entry *scheduler.ScheduleEntry
+	for i := range schedules {
+		if schedules[i].ID == id {
+			entry = &schedules[i]
+			break
+		}
+	}
+	if entry == nil {
+		fmt.Fprintf(os.Stderr, "schedule not found: %s\n", id)
+		return 1
+	}
+
+	// The token itself is never loaded into the command; a placeholder
+	// says whether the keychain has one.
+	cmd, tokenPresent, err := scheduler.DryRunCommand(*entry)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	fmt.Printf("path: %s\n", cmd.Path)
+	fmt.Println("args:")
+	for _, arg := range cmd.Args {
+		fmt.Printf("  %q\n", arg)
+	}
+	fmt.Printf("dir:  %s\n", cmd.Dir)
+	// Everything before os.Environ()'s length is inherited; at run time that
+	// is launchd's environment, not this shell's, so only the overrides show.
+	fmt.Println("env (set by wakeclaude; the rest comes from launchd):")
+	for _, kv := range cmd.Env[min(len(os.Environ()), len(cmd.Env)):] {
+		fmt.Printf("  %s\n", kv)
+	}
+	if cmd.Stdin != nil {
+		fmt.Printf("stdin: the prompt (%d bytes; too long for an argument)\n", len(entry.Prompt))
+	}
+	if entry.PreCommand != "" {
+		fmt.Printf("before: /bin/sh -c %q\n", entry.PreCommand)
+	}
+	if entry.PostCommand != "" {
+		fmt.Printf("after:  /bin/sh -c %q\n", entry.PostCommand)
+	}
+	if !tokenPresent {
+		fmt.Fprintf(os.Stderr, "warning: no setup token in the keychain; run %s\n", app.ClaudeSetupTokenCmd)
+	}
+	return 0
+}
@@ -0,0 +1,111 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+
+	"wakeclaude/internal/scheduler"
+)
+
+// Fields rewritten after every run; left out by default so exports diff cleanly.
+var volatileExportFields = []string{"nextRun", "wakeTime", "updatedAt"}
+
+func runExport(args []string) int {
+	fs := flag.NewFlagSet("wakeclaude export", flag.ContinueOnError)
+	fs.SetOutput(os.Stderr)
+
+	var ndjson bool
+	var full bool
+	var output string
+	fs.BoolVar(&ndjson, "ndjson", false, "Write one schedule per line")
+	fs.BoolVar(&full, "full", false, "Include fields that change on every run (nextRun, wakeTime, updatedAt)")
+	fs.StringVar(&output, "output", "", "Write to this file instead of stdout")
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return 0
+		}
+		return 2
+	}
+
+	store, err := scheduler.DefaultStore()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	schedules, err := store.LoadSchedules()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	data, err := exportSchedules(schedules, ndjson, full)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	if output != "" {
+		if err := os.WriteFile(output, data, 0o644); err != nil {
+			fmt.Fprintf(os.Stderr, "write %s: %v\n", output, err)
+			return 1
+		}
+		fmt.Fprintf(os.Stderr, "Exported %d schedule(s) to %s\n", len(schedules), output)
+		return 0
+	}
+	_, _ = os.Stdout.Write(data)
+	return 0
+}
+
+// exportSchedules orders schedules by creation and keys alphabetically so the
+// same schedules always produce the same bytes.
+func exportSchedules(schedules []scheduler.ScheduleEntry, ndjson, full bool) ([]byte, error) {
+	sorted := append([]scheduler.ScheduleEntry(nil), schedules...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sorted[i].CreatedAt.Equal(sorted[j].CreatedAt) {
+			return sorted[i].ID < sorted[j].ID
+		}
+		return sorted[i].CreatedAt.Before(sorted[j].CreatedAt)
+	})
+
+	records := make([]map[string]any, 0, len(sorted))
+	for _, entry := range sorted {
+		raw, err := json.Marshal(entry)
+		if err != nil {
+			return nil, fmt.Errorf("encode schedule %s: %w", entry.ID, err)
+		}
+		var record map[string]any
+		if err := json.Unmarshal(raw, &record); err != nil {
+			return nil, fmt.Errorf("encode schedule %s: %w", entry.ID, err)
+		}
+		if !full {
+			for _, field := range volatileExportFields {
+				delete(record, field)
+			}
+		}
+		records = append(records, record)
+	}
+
+	if !ndjson {
+		data, err := json.MarshalIndent(records, "", "  ")
+		if err != nil {
+			return nil, fmt.Errorf("encode schedules: %w", err)
+		}
+		return append(data, '\n'), nil
+	}
+
+	var buf bytes.Buffer
+	for _, record := range records {
+		line, err := json.Marshal(record)
+		if err != nil {
+			return nil, fmt.Errorf("encode schedules: %w", err)
+		}
+		buf.Write(line)
+		buf.WriteByte('\n')
+	}
+	return buf.Bytes(), nil
+}
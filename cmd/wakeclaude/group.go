@@ -0,0 +1,173 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"wakeclaude/internal/scheduler"
+)
+
+func runGroup(args []string) int {
+	fs := flag.NewFlagSet("wakeclaude group", flag.ContinueOnError)
+	fs.SetOutput(os.Stderr)
+
+	var ids string
+	var clear bool
+	fs.StringVar(&ids, "id", "", "Schedule ids to move into the group (comma-separated)")
+	fs.BoolVar(&clear, "clear", false, "Remove the --id schedules from their group")
+	// Accept the group name before or after the flags.
+	var names []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			if errors.Is(err, flag.ErrHelp) {
+				return 0
+			}
+			return 2
+		}
+		if fs.NArg() == 0 {
+			break
+		}
+		names = append(names, fs.Arg(0))
+		args = fs.Args()[1:]
+	}
+
+	store, err := scheduler.DefaultStore()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	schedules, err := store.LoadSchedules()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	if len(names) == 0 && ids == "" {
+		printGroups(schedules)
+		return 0
+	}
+	if ids == "" || (clear && len(names) != 0) || (!clear && len(names) != 1) {
+		fmt.Fprintln(os.Stderr, "usage: wakeclaude group [<name> --id <ids> | --clear --id <ids>]")
+		return 2
+	}
+	name := ""
+	if !clear {
+		name = strings.TrimSpace(names[0])
+		if scheduler.GroupSlug(name) == "" {
+			fmt.Fprintf(os.Stderr, "invalid group name: %q\n", names[0])
+			return 2
+		}
+	}
+
+	wanted := make(map[string]bool)
+	for _, id := range strings.Split(ids, ",") {
+		if id = strings.TrimSpace(id); id != "" {
+			wanted[id] = true
+		}
+	}
+	now := time.Now()
+	changed := 0
+	for i := range schedules {
+		if !wanted[schedules[i].ID] {
+			continue
+		}
+		delete(wanted, schedules[i].ID)
+		schedules[i].GroupID = scheduler.GroupSlug(name)
+		schedules[i].GroupName = name
+		schedules[i].UpdatedAt = now
+		changed++
+	}
+	for id := range wanted {
+		fmt.Fprintf(os.Stderr, "schedule not found: %s\n", id)
+	}
+	if len(wanted) > 0 {
+		return 1
+	}
+	if err := store.SaveSchedules(schedules); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	if clear {
+		fmt.Printf("Removed %d schedule(s) from their group.\n", changed)
+	} else {
+		fmt.Printf("Moved %d schedule(s) into @%s.\n", changed, scheduler.GroupSlug(name))
+	}
+	return 0
+}
+
+func printGroups(schedules []scheduler.ScheduleEntry) {
+	labels := make(map[string]string)
+	counts := make(map[string]int)
+	for _, entry := range schedules {
+		if entry.GroupID == "" {
+			continue
+		}
+		labels[entry.GroupID] = scheduler.GroupLabel(entry)
+		counts[entry.GroupID]++
+	}
+	if len(counts) == 0 {
+		fmt.Println("No groups.")
+		return
+	}
+	ids := make([]string, 0, len(counts))
+	for id := range counts {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	for _, id := range ids {
+		fmt.Printf("%s  %d schedule(s)\n", labels[id], counts[id])
+	}
+}
+
+func pauseGroup(store *scheduler.Store, schedules []scheduler.ScheduleEntry, groupID string, until time.Time) int {
+	now := time.Now()
+	changed := 0
+	for _, entry := range scheduler.GroupMembers(schedules, groupID) {
+		if entry.Schedule.Type == "once" {
+			continue
+		}
+		entry.PausedUntil = until
+		entry.UpdatedAt = now
+		if err := store.UpdateSchedule(entry); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+		changed++
+	}
+	if until.IsZero() {
+		fmt.Printf("Resumed %d schedule(s) in @%s.\n", changed, groupID)
+	} else {
+		fmt.Printf("Paused %d schedule(s) in @%s until %s (%s).\n", changed, groupID, until.Format(time.RFC1123), scheduler.RelativeLabel(until, now))
+	}
+	return 0
+}
+
+func deleteGroup(store *scheduler.Store, schedules []scheduler.ScheduleEntry, groupID string) int {
+	members := scheduler.GroupMembers(schedules, groupID)
+	if len(members) == 0 {
+		fmt.Fprintln(os.Stderr, "group not found")
+		return 1
+	}
+	var commands []string
+	for _, entry := range members {
+		commands = append(commands, scheduler.RemoveCommands(entry)...)
+	}
+	if err := ensureSudoFor(commands); err != nil {
+		fmt.Fprintln(os.Stderr, sudoFailure(err, "sudo required to delete wakeclaude schedule"))
+		return 1
+	}
+	for _, entry := range members {
+		if err := deleteSchedule(store, entry); err != nil {
+			fmt.Fprintf(os.Stderr, "%s: %v\n", entry.ID, err)
+			return 1
+		}
+	}
+	fmt.Printf("Deleted %d schedule(s) in @%s.\n", len(members), groupID)
+	fmt.Println("Restore any of them with: wakeclaude restore <id>")
+	return 0
+}
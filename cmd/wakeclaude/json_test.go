@@ -0,0 +1,70 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"testing"
+)
+
+// captureStdout runs fn with os.Stdout on a pipe and returns what reached it.
+func captureStdout(t *testing.T, fn func()) string {
+	t.Helper()
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	stdout := os.Stdout
+	os.Stdout = w
+	defer func() { os.Stdout = stdout }()
+	fn()
+	w.Close()
+	data, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+func TestJSONStdoutHoldsOnlyTheDocument(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	savedStdin, savedStderr, savedExplain := os.Stdin, os.Stderr, explainSudo
+	defer func() { os.Stdin, os.Stderr, explainSudo = savedStdin, savedStderr, savedExplain }()
+	devNull, err := os.OpenFile(os.DevNull, os.O_RDWR, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer devNull.Close()
+	// An empty stdin declines the sudo listing, so sudo itself never runs.
+	os.Stdin, os.Stderr, explainSudo = devNull, devNull, true
+
+	out := captureStdout(t, func() {
+		if err := ensureSudoFor([]string{"pmset repeat cancel"}); !errors.Is(err, errSudoDeclined) {
+			t.Errorf("ensureSudoFor: got %v, want %v", err, errSudoDeclined)
+		}
+	})
+	if out != "" {
+		t.Errorf("sudo listing went to stdout: %q", out)
+	}
+
+	out = captureStdout(t, func() {
+		release := holdStdout(true)
+		defer release()
+		fmt.Println("Schedule deleted.")
+		release()
+		printJSON(struct {
+			Deleted []string `json:"deleted"`
+		}{[]string{"abc123"}})
+	})
+	var doc struct {
+		Deleted []string `json:"deleted"`
+	}
+	if err := json.Unmarshal([]byte(out), &doc); err != nil {
+		t.Fatalf("stdout is not a single json document: %v\n%s", err, out)
+	}
+	if len(doc.Deleted) != 1 || doc.Deleted[0] != "abc123" {
+		t.Errorf("got %+v, want the deleted id", doc)
+	}
+}
@@ -0,0 +1,49 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"wakeclaude/internal/app"
+	"wakeclaude/internal/scheduler"
+)
+
+func printScheduleList(store *scheduler.Store, asJSON bool) int {
+	schedules, err := store.LoadSchedules()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	sortSchedules(schedules)
+
+	if asJSON {
+		if schedules == nil {
+			schedules = []scheduler.ScheduleEntry{}
+		}
+		data, err := json.MarshalIndent(schedules, "", "  ")
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+		fmt.Println(string(data))
+		return 0
+	}
+
+	if len(schedules) == 0 {
+		fmt.Println("No schedules.")
+		return 0
+	}
+	for _, entry := range schedules {
+		next := "next -"
+		if !entry.NextRun.IsZero() {
+			next = "next " + entry.NextRun.Format(time.RFC3339)
+		}
+		if entry.Disabled {
+			next = "disabled"
+		}
+		fmt.Printf("%s  %s  %s  %s\n", entry.ID, scheduler.ScheduleLabel(entry), next, app.DisplayProjectPath(entry.ProjectPath))
+	}
+	return 0
+}
@@ -1,12 +1,15 @@
 package main
 
 import (
+	"bufio"
+	"encoding/json"
 	"errors"
 	"flag"
 	"fmt"
 	"os"
 	"os/user"
 	"path/filepath"
+	"runtime"
 	"sort"
 	"strconv"
 	"strings"
@@ -23,7 +26,39 @@ var (
 	buildDate = "unknown"
 )
 
+var modelOptions = []app.ModelOption{
+	{Label: "Default (auto)", Value: "auto"},
+	{Label: "Opus", Value: "opus"},
+	{Label: "Sonnet", Value: "sonnet"},
+	{Label: "Haiku", Value: "haiku"},
+}
+
 func main() {
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "add":
+			os.Exit(runAdd(os.Args[2:]))
+		case "status":
+			os.Exit(runStatus(os.Args[2:]))
+		case "set-permission":
+			os.Exit(runSetPermission(os.Args[2:]))
+		case "check-update":
+			os.Exit(runCheckUpdate(os.Args[2:]))
+		case "shift":
+			os.Exit(runShift(os.Args[2:]))
+		case "export":
+			os.Exit(runExport(os.Args[2:]))
+		case "group":
+			os.Exit(runGroup(os.Args[2:]))
+		case "trash":
+			os.Exit(runTrash(os.Args[2:]))
+		case "restore":
+			os.Exit(runRestore(os.Args[2:]))
+		case "sudo-refresh":
+			os.Exit(runSudoRefresh(os.Args[2:]))
+		}
+	}
+
 	fs := flag.NewFlagSet("wakeclaude", flag.ContinueOnError)
 	fs.SetOutput(os.Stderr)
 
@@ -32,11 +67,29 @@ func main() {
 	var showHelp bool
 	fs.StringVar(&projectsRoot, "projects-root", "", "Root directory for Claude projects (default: ~/.claude/projects)")
 	fs.StringVar(&runID, "run", "", "Run a scheduled job by id (internal)")
+	var runNowID string
+	fs.StringVar(&runNowID, "run-now", "", "Run a schedule by id right away without rescheduling it")
 	fs.BoolVar(&showHelp, "help", false, "Show help")
 	fs.BoolVar(&showHelp, "h", false, "Show help")
 	var showVersion bool
 	fs.BoolVar(&showVersion, "version", false, "Show version")
 	fs.BoolVar(&showVersion, "v", false, "Show version")
+	var deleteIDs string
+	fs.StringVar(&deleteIDs, "delete", "", "Delete schedules by id (comma-separated) and exit")
+	var list bool
+	var asJSON bool
+	fs.BoolVar(&list, "list", false, "Print schedules and exit")
+	fs.BoolVar(&asJSON, "json", false, "With --list or --delete, print the result as json")
+	var exportPath string
+	var importPath string
+	fs.StringVar(&exportPath, "export", "", "Back up every schedule to this file (- for stdout) and exit")
+	fs.StringVar(&importPath, "import", "", "Schedule everything in a backup file that isn't scheduled yet and exit")
+	var dryRunID string
+	fs.StringVar(&dryRunID, "dry-run", "", "Print the claude command a schedule would run (token redacted) and exit")
+	var resync bool
+	fs.BoolVar(&resync, "resync", false, "Recompute out-of-date next runs and reinstall their launchd jobs and wakes, then exit")
+	var skipVerify bool
+	fs.BoolVar(&skipVerify, "skip-verify", false, "Don't check the setup token with claude before saving a new schedule")
 
 	if err := fs.Parse(os.Args[1:]); err != nil {
 		os.Exit(2)
@@ -68,6 +121,31 @@ func main() {
 		}
 		return
 	}
+	if runNowID != "" {
+		if err := scheduler.RunScheduleOnce(store, runNowID); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		return
+	}
+	if deleteIDs != "" {
+		os.Exit(deleteByID(store, deleteIDs, asJSON))
+	}
+	if list || asJSON {
+		os.Exit(printScheduleList(store, asJSON))
+	}
+	if exportPath != "" {
+		os.Exit(exportBackup(store, exportPath))
+	}
+	if importPath != "" {
+		os.Exit(importBackup(store, importPath))
+	}
+	if dryRunID != "" {
+		os.Exit(dryRun(store, dryRunID))
+	}
+	if resync {
+		os.Exit(resyncSchedules(store))
+	}
 
 	projects, projectsErr := app.DiscoverProjects(projectsRoot)
 
@@ -76,20 +154,12 @@ func main() {
 		fmt.Fprintln(os.Stderr, err)
 		os.Exit(1)
 	}
-	sort.Slice(schedules, func(i, j int) bool {
-		if schedules[i].NextRun.Equal(schedules[j].NextRun) {
-			return schedules[i].CreatedAt.Before(schedules[j].CreatedAt)
-		}
-		if schedules[i].NextRun.IsZero() {
-			return false
-		}
-		if schedules[j].NextRun.IsZero() {
-			return true
-		}
-		return schedules[i].NextRun.Before(schedules[j].NextRun)
-	})
+	sortSchedules(schedules)
+	warnDrift(schedules)
 
-	logs, err := store.LoadLogs(scheduler.MaxRunLogs)
+	_, _ = store.RecoverOrphanLogs(-1, -1)
+	runMax, _, _ := scheduler.LogRetention()
+	logs, err := store.LoadLogs(runMax)
 	if err != nil {
 		fmt.Fprintln(os.Stderr, err)
 		os.Exit(1)
@@ -106,25 +176,39 @@ func main() {
 		}
 	}
 
-	models := []app.ModelOption{
-		{Label: "Default (auto)", Value: "auto"},
-		{Label: "Opus", Value: "opus"},
-		{Label: "Sonnet", Value: "sonnet"},
-		{Label: "Haiku", Value: "haiku"},
+	cfg, err := app.LoadConfig()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "warning: %v; using built-in defaults\n", err)
+	}
+	if cfg.DefaultPermissionMode != "" && !knownPermissionMode(cfg.DefaultPermissionMode) {
+		fmt.Fprintf(os.Stderr, "warning: unknown defaultPermissionMode %q in config; using acceptEdits\n", cfg.DefaultPermissionMode)
+		cfg.DefaultPermissionMode = ""
 	}
 
+	updateCh := make(chan *app.UpdateInfo, 1)
+	go func() { updateCh <- startupUpdateCheck() }()
 	action, err := tui.Run(tui.Input{
-		Projects:    projects,
-		ProjectsErr: projectsErr,
-		Schedules:   schedules,
-		Logs:        logs,
-		Models:      models,
-		ClaudeReady: claudeReady,
-		InstallCmd:  app.ClaudeInstallCmd,
-		TokenReady:  tokenReady,
-		TokenErr:    tokenErr,
-		SetupCmd:    app.ClaudeSetupTokenCmd,
+		Projects:          projects,
+		ProjectsErr:       projectsErr,
+		Schedules:         schedules,
+		Logs:              logs,
+		Models:            modelOptions,
+		ClaudeReady:       claudeReady,
+		InstallCmd:        app.ClaudeInstallCmd,
+		TokenReady:        tokenReady,
+		TokenErr:          tokenErr,
+		SetupCmd:          app.ClaudeSetupTokenCmd,
+		DefaultModel:      cfg.DefaultModel,
+		DefaultPermission: cfg.DefaultPermissionMode,
+		QuickDelete:       cfg.QuickDelete,
 	})
+	select {
+	case update := <-updateCh:
+		if update != nil {
+			printUpdateHint(*update)
+		}
+	default:
+	}
 	if err != nil {
 		if errors.Is(err, tui.ErrUserQuit) {
 			return
@@ -140,22 +224,11 @@ func main() {
 			fmt.Fprintln(os.Stderr, err)
 			os.Exit(1)
 		}
-		if err := scheduler.EnsureSudo(); err != nil {
-			fmt.Fprintln(os.Stderr, "sudo required to schedule wakeclaude")
-			os.Exit(1)
-		}
-		if _, err := store.AddSchedule(entry); err != nil {
-			fmt.Fprintln(os.Stderr, err)
-			os.Exit(1)
-		}
-		if err := scheduler.EnsureLaunchd(entry); err != nil {
-			_, _ = store.DeleteSchedule(entry.ID)
+		if err := ensureTokenPresent(entry, !skipVerify); err != nil {
 			fmt.Fprintln(os.Stderr, err)
 			os.Exit(1)
 		}
-		if err := scheduler.ScheduleWake(entry, entry.WakeTime); err != nil {
-			_, _ = store.DeleteSchedule(entry.ID)
-			_ = scheduler.RemoveLaunchd(entry)
+		if err := createSchedule(store, entry); err != nil {
 			fmt.Fprintln(os.Stderr, err)
 			os.Exit(1)
 		}
@@ -175,28 +248,42 @@ func main() {
 			fmt.Fprintln(os.Stderr, err)
 			os.Exit(1)
 		}
-		if err := scheduler.EnsureSudo(); err != nil {
-			fmt.Fprintln(os.Stderr, "sudo required to update wakeclaude")
+		if err := ensureSudoFor(append(scheduler.RemoveCommands(current), scheduler.InstallCommands(entry)...)); err != nil {
+			fmt.Fprintln(os.Stderr, sudoFailure(err, "sudo required to update wakeclaude"))
 			os.Exit(1)
 		}
-		_ = scheduler.RemoveLaunchd(current)
-		if err := scheduler.CancelWake(current); err != nil {
-			fmt.Fprintln(os.Stderr, "warning: failed to cancel previous wake schedule:", err)
-		}
-		if err := store.UpdateSchedule(entry); err != nil {
+		if err := replaceSchedule(store, current, entry); err != nil {
 			fmt.Fprintln(os.Stderr, err)
 			os.Exit(1)
 		}
-		if err := scheduler.EnsureLaunchd(entry); err != nil {
-			fmt.Fprintln(os.Stderr, err)
+		printUpdated(entry)
+	case tui.ActionReplace:
+		current, ok := findSchedule(schedules, action.ScheduleID)
+		if !ok || action.Entry == nil {
+			fmt.Fprintln(os.Stderr, "schedule not found")
+			os.Exit(1)
+		}
+		if current.Disabled != action.Entry.Disabled && scheduler.CanToggleInPlace(*action.Entry) {
+			if err := toggleSchedule(store, current, *action.Entry); err != nil {
+				fmt.Fprintln(os.Stderr, err)
+				os.Exit(1)
+			}
+			printUpdated(*action.Entry)
+			break
+		}
+		if err := ensureSudoFor(append(scheduler.RemoveCommands(current), scheduler.InstallCommands(*action.Entry)...)); err != nil {
+			fmt.Fprintln(os.Stderr, sudoFailure(err, "sudo required to update wakeclaude"))
 			os.Exit(1)
 		}
-		if err := scheduler.ScheduleWake(entry, entry.WakeTime); err != nil {
+		if err := replaceSchedule(store, current, *action.Entry); err != nil {
 			fmt.Fprintln(os.Stderr, err)
 			os.Exit(1)
 		}
-		printUpdated(entry)
+		printUpdated(*action.Entry)
 	case tui.ActionDelete:
+		if action.GroupID != "" {
+			os.Exit(deleteGroup(store, schedules, action.GroupID))
+		}
 		if action.ScheduleID == "" {
 			fmt.Fprintln(os.Stderr, "missing schedule id")
 			os.Exit(1)
@@ -206,26 +293,76 @@ func main() {
 			fmt.Fprintln(os.Stderr, "schedule not found")
 			os.Exit(1)
 		}
-		if err := scheduler.EnsureSudo(); err != nil {
-			fmt.Fprintln(os.Stderr, "sudo required to delete wakeclaude schedule")
+		if err := ensureSudoFor(scheduler.RemoveCommands(current)); err != nil {
+			fmt.Fprintln(os.Stderr, sudoFailure(err, "sudo required to delete wakeclaude schedule"))
 			os.Exit(1)
 		}
-		_ = scheduler.RemoveLaunchd(current)
-		if err := scheduler.CancelWake(current); err != nil {
-			fmt.Fprintln(os.Stderr, "warning: failed to cancel wake schedule:", err)
-		}
-		if _, err := store.DeleteSchedule(current.ID); err != nil {
+		if err := deleteSchedule(store, current); err != nil {
 			fmt.Fprintln(os.Stderr, err)
 			os.Exit(1)
 		}
 		printDeleted(current)
+	case tui.ActionPause:
+		if action.GroupID != "" {
+			os.Exit(pauseGroup(store, schedules, action.GroupID, action.PausedUntil))
+		}
+		current, ok := findSchedule(schedules, action.ScheduleID)
+		if !ok {
+			fmt.Fprintln(os.Stderr, "schedule not found")
+			os.Exit(1)
+		}
+		current.PausedUntil = action.PausedUntil
+		current.UpdatedAt = time.Now()
+		if err := store.UpdateSchedule(current); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		printPaused(current)
 	default:
 		return
 	}
 }
 
+func deleteSchedule(store *scheduler.Store, entry scheduler.ScheduleEntry) error {
+	_ = scheduler.Backend.Remove(entry)
+	if err := scheduler.Backend.CancelWake(entry); err != nil {
+		fmt.Fprintln(os.Stderr, "warning: failed to cancel wake schedule:", err)
+	}
+	if _, err := store.DeleteSchedule(entry.ID); err != nil {
+		return err
+	}
+	if err := store.TrashSchedule(entry, time.Now()); err != nil {
+		fmt.Fprintln(os.Stderr, "warning: failed to keep a copy in the trash:", err)
+	}
+	return nil
+}
+
+func sortSchedules(schedules []scheduler.ScheduleEntry) {
+	sort.Slice(schedules, func(i, j int) bool {
+		if schedules[i].NextRun.Equal(schedules[j].NextRun) {
+			return schedules[i].CreatedAt.Before(schedules[j].CreatedAt)
+		}
+		if schedules[i].NextRun.IsZero() {
+			return false
+		}
+		if schedules[j].NextRun.IsZero() {
+			return true
+		}
+		return schedules[i].NextRun.Before(schedules[j].NextRun)
+	})
+}
+
 func printVersion() {
 	fmt.Printf("wakeclaude %s (commit %s, built %s)\n", version, commit, buildDate)
+	fmt.Printf("%s %s/%s\n", runtime.Version(), runtime.GOOS, runtime.GOARCH)
+	switch runtime.GOOS {
+	case "darwin":
+		fmt.Println("platform: macOS (launchd, pmset and keychain supported)")
+	case "linux":
+		fmt.Println("platform: linux (systemd user timers, rtcwake and secret-tool)")
+	default:
+		fmt.Println("platform: unsupported; scheduling needs launchd on macOS or systemd on linux")
+	}
 }
 
 func buildEntry(draft *tui.Draft, existing *scheduler.ScheduleEntry) (scheduler.ScheduleEntry, error) {
@@ -269,6 +406,14 @@ func buildEntry(draft *tui.Draft, existing *scheduler.ScheduleEntry) (scheduler.
 	}
 
 	home, _ := os.UserHomeDir()
+	homeOverride, err := resolveHomeOverride(draft.HomeDir, home)
+	if err != nil {
+		return scheduler.ScheduleEntry{}, err
+	}
+	claudeBinary, err := resolveClaudeBinary(draft.ClaudeBinary)
+	if err != nil {
+		return scheduler.ScheduleEntry{}, err
+	}
 	pathEnv := os.Getenv("PATH")
 	if pathEnv == "" && existing != nil {
 		pathEnv = existing.PathEnv
@@ -277,39 +422,80 @@ func buildEntry(draft *tui.Draft, existing *scheduler.ScheduleEntry) (scheduler.
 		pathEnv = "/usr/local/bin:/usr/bin:/bin:/usr/sbin:/sbin"
 	}
 
-	model := strings.TrimSpace(draft.Model)
-	if model == "" {
-		model = "auto"
+	cfg, _ := app.LoadConfig()
+	modelValue := draft.Model
+	if strings.TrimSpace(modelValue) == "" {
+		modelValue = cfg.DefaultModel
+	}
+	model, ok := scheduler.NormalizeModel(modelValue)
+	if !ok {
+		fmt.Fprintf(os.Stderr, "warning: unrecognized model %q; claude may reject it at run time\n", model)
 	}
 	perm := strings.TrimSpace(draft.Permission)
+	if perm == "" && knownPermissionMode(cfg.DefaultPermissionMode) {
+		perm = cfg.DefaultPermissionMode
+	}
 	if perm == "" {
 		perm = "acceptEdits"
 	}
+	notify := strings.TrimSpace(draft.Notify)
+	if notify == "always" {
+		notify = ""
+	}
 
 	entry := scheduler.ScheduleEntry{
-		ID:             id,
-		ProjectPath:    draft.ProjectPath,
-		SessionID:      draft.SessionID,
-		SessionPath:    draft.SessionPath,
-		NewSession:     draft.NewSession,
-		Model:          model,
-		PermissionMode: perm,
-		Prompt:         strings.TrimSpace(draft.Prompt),
+		ID:                id,
+		ProjectPath:       draft.ProjectPath,
+		SessionID:         draft.SessionID,
+		SessionPath:       draft.SessionPath,
+		NewSession:        draft.NewSession,
+		ForkSession:       draft.ForkSession,
+		Model:             model,
+		PermissionMode:    perm,
+		Prompt:            strings.TrimSpace(draft.Prompt),
+		PreCommand:        strings.TrimSpace(draft.PreCommand),
+		PostCommand:       strings.TrimSpace(draft.PostCommand),
+		Env:               draft.Env,
+		Description:       strings.TrimSpace(draft.Description),
+		Tags:              draft.Tags,
+		GroupID:           scheduler.GroupSlug(draft.Group),
+		GroupName:         strings.TrimSpace(draft.Group),
+		Notify:            notify,
+		NotifySound:       draft.NotifySound,
+		MinBatteryPercent: draft.MinBattery,
+		RequireAC:         draft.RequireAC,
+		RequireNetwork:    draft.NetworkHost != "",
+		NetworkHost:       networkHost(draft.NetworkHost),
+		RequireDirty:      draft.RequireDirty,
+		Retries:           draft.Retries,
+		RetryDelay:        draft.RetryDelay,
+		OutputFormat:      outputFormat(draft.JSONOutput),
+		LowPriority:       draft.LowPriority,
+		ProcessType:       draft.ProcessType,
 		Schedule: scheduler.Schedule{
-			Type:    draft.Schedule.Type,
-			Date:    draft.Schedule.Date,
-			Time:    draft.Schedule.Time,
-			Weekday: draft.Schedule.Weekday,
+			Type:            draft.Schedule.Type,
+			Date:            draft.Schedule.Date,
+			Time:            draft.Schedule.Time,
+			Times:           draft.Schedule.Times,
+			Weekday:         draft.Schedule.Weekday,
+			Day:             draft.Schedule.Day,
+			Cron:            cronExpr(draft.Schedule),
+			IntervalMinutes: intervalMinutes(draft.Schedule),
+			Event:           draft.Schedule.Event,
+			Latitude:        draft.Schedule.Latitude,
+			Longitude:       draft.Schedule.Longitude,
 		},
-		Timezone:   draft.Schedule.Timezone,
-		CreatedAt:  created,
-		UpdatedAt:  now,
-		BinaryPath: exe,
-		User:       username,
-		UID:        uid,
-		GID:        gid,
-		HomeDir:    home,
-		PathEnv:    pathEnv,
+		Timezone:     draft.Schedule.Timezone,
+		CreatedAt:    created,
+		UpdatedAt:    now,
+		BinaryPath:   exe,
+		ClaudeBinary: claudeBinary,
+		User:         username,
+		UID:          uid,
+		GID:          gid,
+		HomeDir:      home,
+		HomeOverride: homeOverride,
+		PathEnv:      pathEnv,
 	}
 
 	if existing != nil {
@@ -325,10 +511,70 @@ func buildEntry(draft *tui.Draft, existing *scheduler.ScheduleEntry) (scheduler.
 		if entry.HomeDir == "" {
 			entry.HomeDir = existing.HomeDir
 		}
+		if entry.HomeOverride == "" && draft.HomeDir == "" {
+			entry.HomeOverride = existing.HomeOverride
+		}
+		if entry.ClaudeBinary == "" {
+			entry.ClaudeBinary = existing.ClaudeBinary
+		}
+		if draft.MinBattery == 0 && !draft.RequireAC {
+			entry.MinBatteryPercent = existing.MinBatteryPercent
+			entry.RequireAC = existing.RequireAC
+		}
+		if draft.NetworkHost == "" {
+			entry.RequireNetwork = existing.RequireNetwork
+			entry.NetworkHost = existing.NetworkHost
+		}
+		if !draft.JSONOutput {
+			entry.OutputFormat = existing.OutputFormat
+		}
+		if !draft.LowPriority {
+			entry.LowPriority = existing.LowPriority
+		}
+		if draft.NotifySound == "" {
+			entry.NotifySound = existing.NotifySound
+		}
+		if draft.ProcessType == "" && !entry.LowPriority {
+			entry.ProcessType = existing.ProcessType
+		}
+		if !draft.RequireDirty {
+			entry.RequireDirty = existing.RequireDirty
+		}
+		if draft.Retries == 0 {
+			entry.Retries = existing.Retries
+			entry.RetryDelay = existing.RetryDelay
+		}
+		entry.Disabled = existing.Disabled
+		if entry.GroupID == "" {
+			entry.GroupID = existing.GroupID
+			entry.GroupName = existing.GroupName
+		}
 		if entry.PathEnv == "" {
 			entry.PathEnv = existing.PathEnv
 		}
 	}
+	if entry.Timezone == "" {
+		entry.Timezone = app.ScheduleTimezone()
+	}
+	if draft.Schedule.StartDate != "" && entry.Schedule.Type != "once" {
+		start, err := scheduler.ParseStartDate(draft.Schedule.StartDate, entry.Timezone)
+		if err != nil {
+			return scheduler.ScheduleEntry{}, err
+		}
+		entry.StartAt = start
+	}
+
+	// Never point claude at wakeclaude's own scratch dirs (the token verify
+	// project lives there).
+	dir, source := scheduler.ResolveWorkDir(entry)
+	for _, path := range []string{entry.ProjectPath, dir} {
+		if app.IsWakeClaudeInternalPath(path) {
+			return scheduler.ScheduleEntry{}, fmt.Errorf("%s is inside wakeclaude's own data directory; schedule a project outside it", app.HumanizePath(path))
+		}
+	}
+	if source == scheduler.WorkDirFallback {
+		fmt.Fprintf(os.Stderr, "warning: project directory %s not found; runs will start in %s\n", app.DisplayProjectPath(entry.ProjectPath), app.HumanizePath(dir))
+	}
 
 	nextRun, err := scheduler.NextRun(entry, now)
 	if err != nil {
@@ -340,6 +586,229 @@ func buildEntry(draft *tui.Draft, existing *scheduler.ScheduleEntry) (scheduler.
 	return entry, nil
 }
 
+// The tui keeps the last cron expression and interval around while
+// switching types.
+func cronExpr(schedule tui.Schedule) string {
+	if schedule.Type != "cron" {
+		return ""
+	}
+	return schedule.Cron
+}
+
+func intervalMinutes(schedule tui.Schedule) int {
+	if schedule.Type != "interval" {
+		return 0
+	}
+	return schedule.Interval
+}
+
+func outputFormat(jsonOutput bool) string {
+	if jsonOutput {
+		return "json"
+	}
+	return ""
+}
+
+// networkHost leaves the built-in host empty on the entry.
+func networkHost(value string) string {
+	if value == scheduler.DefaultNetworkHost {
+		return ""
+	}
+	return value
+}
+
+func resolveHomeOverride(value, home string) (string, error) {
+	if strings.TrimSpace(value) == "" {
+		return "", nil
+	}
+	path, err := app.NormalizePath(value)
+	if err != nil {
+		return "", fmt.Errorf("resolve home: %w", err)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return "", fmt.Errorf("home not found: %s", app.HumanizePath(path))
+	}
+	if !info.IsDir() {
+		return "", fmt.Errorf("home is not a directory: %s", app.HumanizePath(path))
+	}
+	if path == filepath.Clean(home) {
+		return "", nil
+	}
+	return path, nil
+}
+
+// resolveClaudeBinary turns a --claude-bin value into an absolute path to
+// an executable, or "" to search PATH at run time.
+func resolveClaudeBinary(value string) (string, error) {
+	if strings.TrimSpace(value) == "" {
+		return "", nil
+	}
+	path, err := app.NormalizePath(value)
+	if err != nil {
+		return "", fmt.Errorf("resolve claude binary: %w", err)
+	}
+	if err := scheduler.CheckExecutable(path); err != nil {
+		return "", fmt.Errorf("claude binary: %w", err)
+	}
+	return path, nil
+}
+
+// ensureTokenPresent re-checks the keychain right before a schedule is saved:
+// the token may have been removed since the tui started, and the schedule
+// would only fail at its first run. A missing token can be pasted here.
+// With verify, a stored token is also tried against the claude the entry
+// will run (one small API call), catching an expired one now rather than at
+// the run.
+func ensureTokenPresent(entry scheduler.ScheduleEntry, verify bool) error {
+	token, err := app.LoadOAuthToken()
+	if err == nil && strings.TrimSpace(token) != "" {
+		if !verify {
+			return nil
+		}
+		fmt.Fprintln(os.Stderr, "Checking the setup token...")
+		if err := verifyToken(entry, token); err != nil {
+			return fmt.Errorf("schedule not created: %w (or pass --skip-verify)", err)
+		}
+		return nil
+	}
+	if errors.Is(err, app.ErrKeychainLocked) {
+		return fmt.Errorf("schedule not created: %w; unlock it and try again", err)
+	}
+	fmt.Fprintln(os.Stderr, "The claude setup token is no longer in the keychain.")
+	if !confirm("Paste a new setup token now?") {
+		return fmt.Errorf("schedule not created; run %s, then wakeclaude to set the token", app.ClaudeSetupTokenCmd)
+	}
+	fmt.Fprintf(os.Stderr, "Run %s in another terminal and paste the token: ", app.ClaudeSetupTokenCmd)
+	line, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+	token = strings.TrimSpace(line)
+	if token == "" {
+		return errors.New("schedule not created: no token entered")
+	}
+	if err := verifyToken(entry, token); err != nil {
+		return fmt.Errorf("schedule not created: %w", err)
+	}
+	if err := app.SaveOAuthToken(token); err != nil {
+		return fmt.Errorf("schedule not created: save token: %w", err)
+	}
+	return nil
+}
+
+// verifyToken tries token with the claude binary the entry will run.
+func verifyToken(entry scheduler.ScheduleEntry, token string) error {
+	claudeBin, err := scheduler.ClaudePath(entry)
+	if err != nil {
+		return err
+	}
+	return app.VerifyOAuthToken(claudeBin, token)
+}
+
+func createSchedule(store *scheduler.Store, entry scheduler.ScheduleEntry) error {
+	if err := ensureSudoFor(scheduler.InstallCommands(entry)); err != nil {
+		return errors.New(sudoFailure(err, "sudo required to schedule wakeclaude"))
+	}
+	return installSchedule(store, entry)
+}
+
+// installSchedule saves a new entry and installs its launchd job and wake,
+// once sudo is already granted.
+func installSchedule(store *scheduler.Store, entry scheduler.ScheduleEntry) error {
+	if entry.Disabled {
+		entry.WakeTime = ""
+	}
+	if _, err := store.AddSchedule(entry); err != nil {
+		return err
+	}
+	if entry.Disabled {
+		return nil
+	}
+	if err := scheduler.Backend.Ensure(entry); err != nil {
+		_, _ = store.DeleteSchedule(entry.ID)
+		return err
+	}
+	if err := scheduler.Backend.ScheduleWake(entry, entry.WakeTime); err != nil {
+		_, _ = store.DeleteSchedule(entry.ID)
+		_ = scheduler.Backend.Remove(entry)
+		return err
+	}
+	return nil
+}
+
+var errSudoDeclined = errors.New("cancelled; nothing was changed")
+
+// explainSudo is set by add --explain-sudo; "explainSudo" in the config turns
+// it on everywhere.
+var explainSudo bool
+
+// ensureSudoFor asks for the sudo password when commands need it, first
+// listing them and asking to go ahead if explainSudo is on.
+func ensureSudoFor(commands []string) error {
+	if len(commands) == 0 {
+		return nil
+	}
+	if cfg, err := app.LoadConfig(); explainSudo || (err == nil && cfg.ExplainSudo) {
+		fmt.Fprintln(os.Stderr, "wakeclaude will run these commands with sudo:")
+		for _, command := range commands {
+			fmt.Fprintf(os.Stderr, "  sudo %s\n", command)
+		}
+		if !confirm("Continue?") {
+			return errSudoDeclined
+		}
+	}
+	return scheduler.EnsureSudo()
+}
+
+func sudoFailure(err error, fallback string) string {
+	if errors.Is(err, errSudoDeclined) {
+		return err.Error()
+	}
+	return fallback
+}
+
+func replaceSchedule(store *scheduler.Store, current, entry scheduler.ScheduleEntry) error {
+	_ = scheduler.Backend.Remove(current)
+	if err := scheduler.Backend.CancelWake(current); err != nil {
+		fmt.Fprintln(os.Stderr, "warning: failed to cancel previous wake schedule:", err)
+	}
+	// A disabled schedule keeps its config but has no launchd job or wake.
+	if entry.Disabled {
+		entry.WakeTime = ""
+	}
+	if err := store.UpdateSchedule(entry); err != nil {
+		return err
+	}
+	if entry.Disabled {
+		return nil
+	}
+	if err := scheduler.Backend.Ensure(entry); err != nil {
+		return err
+	}
+	return scheduler.Backend.ScheduleWake(entry, entry.WakeTime)
+}
+
+// toggleSchedule enables or disables a schedule with launchctl, keeping its
+// plist, and falls back to a full replace if launchctl refuses.
+func toggleSchedule(store *scheduler.Store, current, entry scheduler.ScheduleEntry) error {
+	if err := ensureSudoFor(scheduler.ToggleCommands(entry)); err != nil {
+		return errors.New(sudoFailure(err, "sudo required to update wakeclaude"))
+	}
+	if err := scheduler.SetLaunchdEnabled(entry, !entry.Disabled); err != nil {
+		fmt.Fprintf(os.Stderr, "warning: %v; reinstalling the job instead\n", err)
+		return replaceSchedule(store, current, entry)
+	}
+	if entry.Disabled {
+		if err := scheduler.Backend.CancelWake(current); err != nil {
+			fmt.Fprintln(os.Stderr, "warning: failed to cancel wake schedule:", err)
+		}
+		entry.WakeTime = ""
+		return store.UpdateSchedule(entry)
+	}
+	if err := store.UpdateSchedule(entry); err != nil {
+		return err
+	}
+	return scheduler.Backend.ScheduleWake(entry, entry.WakeTime)
+}
+
 func findSchedule(list []scheduler.ScheduleEntry, id string) (scheduler.ScheduleEntry, bool) {
 	for _, entry := range list {
 		if entry.ID == id {
@@ -352,19 +821,117 @@ func findSchedule(list []scheduler.ScheduleEntry, id string) (scheduler.Schedule
 func printScheduled(entry scheduler.ScheduleEntry) {
 	fmt.Println("Scheduled.")
 	fmt.Printf("ID: %s\n", entry.ID)
-	fmt.Printf("Next run: %s (%s)\n", entry.NextRun.Format(time.RFC1123), scheduler.RelativeLabel(entry.NextRun, time.Now()))
-	fmt.Printf("Project: %s\n", app.HumanizePath(entry.ProjectPath))
+	fmt.Printf("Schedule: %s\n", scheduler.ScheduleLabel(entry))
+	fmt.Printf("Next run: %s\n", nextRunLabel(entry))
+	fmt.Printf("Model: %s\n", entry.Model)
+	fmt.Printf("Permission: %s\n", entry.PermissionMode)
+	fmt.Printf("Project: %s\n", app.DisplayProjectPath(entry.ProjectPath))
+	fmt.Printf("Runs in: %s\n", scheduler.WorkDirLabel(entry))
+	printRunAs(entry)
 }
 
 func printUpdated(entry scheduler.ScheduleEntry) {
 	fmt.Println("Schedule updated.")
 	fmt.Printf("ID: %s\n", entry.ID)
-	fmt.Printf("Next run: %s (%s)\n", entry.NextRun.Format(time.RFC1123), scheduler.RelativeLabel(entry.NextRun, time.Now()))
+	fmt.Printf("Next run: %s\n", nextRunLabel(entry))
+	printRunAs(entry)
+}
+
+// RFC1123 without the zone; FormatInZone adds it when it is not local.
+const nextRunLayout = "Mon, 02 Jan 2006 15:04:05"
+
+func nextRunLabel(entry scheduler.ScheduleEntry) string {
+	if entry.Schedule.Type == "login" {
+		return "at your next login"
+	}
+	return fmt.Sprintf("%s (%s)", scheduler.FormatInZone(entry, entry.NextRun, nextRunLayout), scheduler.RelativeLabel(entry.NextRun, time.Now()))
+}
+
+func printPaused(entry scheduler.ScheduleEntry) {
+	if entry.PausedUntil.IsZero() {
+		fmt.Println("Schedule resumed.")
+	} else {
+		fmt.Printf("Schedule paused until %s (%s).\n", entry.PausedUntil.Format(time.RFC1123), scheduler.RelativeLabel(entry.PausedUntil, time.Now()))
+	}
+	fmt.Printf("ID: %s\n", entry.ID)
+}
+
+func printRunAs(entry scheduler.ScheduleEntry) {
+	fmt.Printf("Runs as: %s\n", runAsLabel(entry))
+	if warning := consoleUserWarning(entry); warning != "" {
+		fmt.Fprintln(os.Stderr, warning)
+	}
+	account, warning := tokenAccountStatus(entry)
+	if account != "" {
+		fmt.Printf("Token: keychain account %s\n", account)
+	}
+	if warning != "" {
+		fmt.Fprintln(os.Stderr, warning)
+	}
+}
+
+func runAsLabel(entry scheduler.ScheduleEntry) string {
+	return fmt.Sprintf("%s (uid %d, home %s)", entry.User, entry.UID, app.HumanizePath(scheduler.RunHome(entry)))
+}
+
+func consoleUserWarning(entry scheduler.ScheduleEntry) string {
+	console, mismatch := scheduler.ConsoleUserMismatch(entry)
+	if !mismatch {
+		return ""
+	}
+	name := console.Username
+	if name == "" {
+		name = fmt.Sprintf("uid %d", console.UID)
+	}
+	return fmt.Sprintf("warning: logged-in console user is %s, but this schedule runs as %s and reads that user's keychain token", name, entry.User)
+}
+
+// Scheduled runs look the token up by entry.User only, without the
+// service-wide fallback the interactive lookup has.
+func tokenAccountStatus(entry scheduler.ScheduleEntry) (string, string) {
+	account, err := app.OAuthTokenAccount(entry.User)
+	if errors.Is(err, os.ErrNotExist) {
+		return "", fmt.Sprintf("warning: no setup token in the keychain; run %s before the first run", app.ClaudeSetupTokenCmd)
+	}
+	if err != nil {
+		return "", ""
+	}
+	if account == "" {
+		account = "(none)"
+	}
+	if entry.User != "" && account != entry.User {
+		return account, fmt.Sprintf("warning: the setup token is saved under keychain account %s, but scheduled runs look it up as %s; save the token again while logged in as %s", account, entry.User, entry.User)
+	}
+	return account, ""
+}
+
+// holdStdout points os.Stdout at stderr until the returned func is called,
+// so sudo's output and anything else printed on the way can't land ahead of
+// a --json document.
+func holdStdout(asJSON bool) (release func()) {
+	if !asJSON {
+		return func() {}
+	}
+	stdout := os.Stdout
+	os.Stdout = os.Stderr
+	return func() { os.Stdout = stdout }
+}
+
+// printJSON is the --json form of the confirmations above, for scripts.
+func printJSON(value any) int {
+	data, err := json.MarshalIndent(value, "", "  ")
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	fmt.Println(string(data))
+	return 0
 }
 
 func printDeleted(entry scheduler.ScheduleEntry) {
 	fmt.Println("Schedule deleted.")
 	fmt.Printf("ID: %s\n", entry.ID)
+	fmt.Printf("Restore it with: wakeclaude restore %s\n", entry.ID)
 }
 
 func printUsage() {
@@ -372,10 +939,36 @@ func printUsage() {
 	fmt.Fprintln(os.Stderr, "")
 	fmt.Fprintln(os.Stderr, "Usage:")
 	fmt.Fprintln(os.Stderr, "  wakeclaude [--projects-root <path>]")
+	fmt.Fprintln(os.Stderr, "  wakeclaude --list [--json]")
+	fmt.Fprintln(os.Stderr, "  wakeclaude --delete <id>[,<id>...] [--json]")
+	fmt.Fprintln(os.Stderr, "  wakeclaude --run-now <id>")
+	fmt.Fprintln(os.Stderr, "  wakeclaude --dry-run <id>")
+	fmt.Fprintln(os.Stderr, "  wakeclaude --resync")
+	fmt.Fprintln(os.Stderr, "  wakeclaude --export <file> | --import <file>")
+	fmt.Fprintln(os.Stderr, "  wakeclaude status")
+	fmt.Fprintln(os.Stderr, "  wakeclaude add --project <path> --prompt <text> (--once|--daily|--weekly|--monthly --time <HH:MM> | --at-login) [--json] [flags]")
+	fmt.Fprintln(os.Stderr, "  wakeclaude set-permission <mode> [--from <mode>] [--id <ids>] [--yes]")
+	fmt.Fprintln(os.Stderr, "  wakeclaude shift <+1h|-30m> [--type <type>] [--tag <tag>] [--id <ids>] [--yes]")
+	fmt.Fprintln(os.Stderr, "  wakeclaude export [--ndjson] [--full] [--output <file>]")
+	fmt.Fprintln(os.Stderr, "  wakeclaude group [<name> --id <ids> | --clear --id <ids>]")
+	fmt.Fprintln(os.Stderr, "  wakeclaude trash")
+	fmt.Fprintln(os.Stderr, "  wakeclaude restore <id> [--json]")
+	fmt.Fprintln(os.Stderr, "  wakeclaude check-update [--on-start on|off]")
+	fmt.Fprintln(os.Stderr, "  wakeclaude sudo-refresh [--clear]")
 	fmt.Fprintln(os.Stderr, "")
 	fmt.Fprintln(os.Stderr, "Flags:")
 	fmt.Fprintln(os.Stderr, "  --projects-root   Root directory for Claude projects (default: ~/.claude/projects)")
+	fmt.Fprintln(os.Stderr, "  --list            Print schedules and exit (add --json for machine-readable output)")
+	fmt.Fprintln(os.Stderr, "  --delete          Delete schedules by id without the tui")
+	fmt.Fprintln(os.Stderr, "  --run-now         Run a schedule now to test it (logged, not rescheduled)")
+	fmt.Fprintln(os.Stderr, "  --dry-run         Print the exact command, dir and env a schedule's run would use (token redacted)")
+	fmt.Fprintln(os.Stderr, "  --resync          Fix schedules whose next run went stale (e.g. after a timezone change)")
+	fmt.Fprintln(os.Stderr, "  --skip-verify     Save new schedules without checking the setup token with claude first")
+	fmt.Fprintln(os.Stderr, "  --export          Back up every schedule to a file (- for stdout)")
+	fmt.Fprintln(os.Stderr, "  --import          Schedule the entries of a backup on this mac, skipping ids already scheduled")
 	fmt.Fprintln(os.Stderr, "  --run             Internal: run a scheduled task by id")
 	fmt.Fprintln(os.Stderr, "  --help, -h        Show help")
 	fmt.Fprintln(os.Stderr, "  --version, -v     Show version")
+	fmt.Fprintln(os.Stderr, "")
+	fmt.Fprintln(os.Stderr, "Run `wakeclaude add --help` for non-interactive scheduling flags.")
 }
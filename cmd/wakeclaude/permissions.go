@@ -0,0 +1,122 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"wakeclaude/internal/scheduler"
+)
+
+func runSetPermission(args []string) int {
+	fs := flag.NewFlagSet("wakeclaude set-permission", flag.ContinueOnError)
+	fs.SetOutput(os.Stderr)
+
+	var from string
+	var ids string
+	var yes bool
+	fs.StringVar(&from, "from", "", "Only change schedules currently using this mode")
+	fs.StringVar(&ids, "id", "", "Only change these schedule ids (comma-separated)")
+	fs.BoolVar(&yes, "yes", false, "Apply without asking for confirmation")
+
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return 0
+		}
+		return 2
+	}
+	if fs.NArg() != 1 {
+		fmt.Fprintln(os.Stderr, "usage: wakeclaude set-permission <mode> [--from <mode>] [--id <ids>] [--yes]")
+		return 2
+	}
+	mode := strings.TrimSpace(fs.Arg(0))
+	if !knownPermissionMode(mode) {
+		fmt.Fprintf(os.Stderr, "unknown permission mode: %s (use %s)\n", mode, strings.Join(permissionModes, ", "))
+		return 2
+	}
+	if from != "" && !knownPermissionMode(from) {
+		fmt.Fprintf(os.Stderr, "unknown permission mode: %s (use %s)\n", from, strings.Join(permissionModes, ", "))
+		return 2
+	}
+
+	store, err := scheduler.DefaultStore()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	schedules, err := store.LoadSchedules()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	wanted := make(map[string]bool)
+	for _, id := range strings.Split(ids, ",") {
+		if id = strings.TrimSpace(id); id != "" {
+			wanted[id] = true
+		}
+	}
+
+	var changed []int
+	for i, entry := range schedules {
+		if len(wanted) > 0 && !wanted[entry.ID] {
+			continue
+		}
+		current := entry.PermissionMode
+		if current == "" {
+			current = "default"
+		}
+		if from != "" && current != from {
+			continue
+		}
+		if current == mode {
+			continue
+		}
+		changed = append(changed, i)
+	}
+	if len(changed) == 0 {
+		fmt.Println("No schedules to change.")
+		return 0
+	}
+
+	fmt.Printf("Set permission mode to %s on %d schedule(s):\n", mode, len(changed))
+	for _, i := range changed {
+		entry := schedules[i]
+		current := entry.PermissionMode
+		if current == "" {
+			current = "default"
+		}
+		fmt.Printf("  %s  %s  (%s)\n", entry.ID, scheduler.ScheduleLabel(entry), current)
+	}
+	if !yes && !confirm("Apply?") {
+		fmt.Println("Cancelled.")
+		return 1
+	}
+
+	now := time.Now()
+	for _, i := range changed {
+		schedules[i].PermissionMode = mode
+		schedules[i].UpdatedAt = now
+	}
+	if err := store.SaveSchedules(schedules); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	fmt.Printf("Updated %d schedule(s).\n", len(changed))
+	return 0
+}
+
+func confirm(question string) bool {
+	// Prompts go to stderr so a --json result is all stdout holds.
+	fmt.Fprintf(os.Stderr, "%s [y/N] ", question)
+	answer, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil && answer == "" {
+		return false
+	}
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	return answer == "y" || answer == "yes"
+}
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"wakeclaude/internal/scheduler"
+)
+
+// warnDrift prints one line when schedules' next runs have gone stale, so
+// they aren't missed silently until someone looks at the list.
+func warnDrift(schedules []scheduler.ScheduleEntry) {
+	now := time.Now()
+	count := 0
+	for _, entry := range schedules {
+		if scheduler.ValidateSchedule(entry, now) != nil {
+			count++
+		}
+	}
+	if count > 0 {
+		fmt.Fprintf(os.Stderr, "warning: %d schedule(s) have an out-of-date next run (did the timezone change?); run wakeclaude --resync\n", count)
+	}
+}
+
+func resyncSchedules(store *scheduler.Store) int {
+	schedules, err := store.LoadSchedules()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	now := time.Now()
+	var currents, resynced []scheduler.ScheduleEntry
+	failed := false
+	for _, entry := range schedules {
+		problem := scheduler.ValidateSchedule(entry, now)
+		if problem == nil {
+			continue
+		}
+		next, err := scheduler.ResyncNextRun(entry, now)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%s  %s: %v (%v; delete it or run it with --run-now)\n", entry.ID, scheduler.ScheduleLabel(entry), problem, err)
+			failed = true
+			continue
+		}
+		fmt.Printf("%s  %s: %v\n", entry.ID, scheduler.ScheduleLabel(entry), problem)
+		current := entry
+		entry.NextRun = next
+		entry.WakeTime = scheduler.FormatPMSet(next)
+		entry.UpdatedAt = now
+		currents = append(currents, current)
+		resynced = append(resynced, entry)
+	}
+	if len(resynced) == 0 {
+		if failed {
+			return 1
+		}
+		fmt.Println("All schedules are in sync.")
+		return 0
+	}
+
+	var commands []string
+	for i := range resynced {
+		commands = append(commands, scheduler.RemoveCommands(currents[i])...)
+		commands = append(commands, scheduler.InstallCommands(resynced[i])...)
+	}
+	if err := ensureSudoFor(commands); err != nil {
+		fmt.Fprintln(os.Stderr, sudoFailure(err, "sudo required to update wakeclaude"))
+		return 1
+	}
+	for i := range resynced {
+		if err := replaceSchedule(store, currents[i], resynced[i]); err != nil {
+			fmt.Fprintf(os.Stderr, "%s: %v\n", resynced[i].ID, err)
+			return 1
+		}
+	}
+	fmt.Printf("Resynced %d schedule(s).\n", len(resynced))
+	if failed {
+		return 1
+	}
+	return 0
+}
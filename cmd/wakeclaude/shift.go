@@ -0,0 +1,204 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"wakeclaude/internal/scheduler"
+)
+
+func runShift(args []string) int {
+	fs := flag.NewFlagSet("wakeclaude shift", flag.ContinueOnError)
+	fs.SetOutput(os.Stderr)
+
+	var scheduleType string
+	var tag string
+	var ids string
+	var yes bool
+	fs.StringVar(&scheduleType, "type", "", "Only shift schedules of this type (once, daily, weekly, monthly)")
+	fs.StringVar(&tag, "tag", "", "Only shift schedules with this tag")
+	fs.StringVar(&ids, "id", "", "Only shift these schedule ids (comma-separated)")
+	fs.BoolVar(&yes, "yes", false, "Apply without asking for confirmation")
+
+	// Let "-30m" through as the delta instead of a flag.
+	if len(args) > 0 && strings.HasPrefix(args[0], "-") && len(args[0]) > 1 && args[0][1] >= '0' && args[0][1] <= '9' {
+		args = append(args[1:], "--", args[0])
+	}
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return 0
+		}
+		return 2
+	}
+	if fs.NArg() != 1 {
+		fmt.Fprintln(os.Stderr, "usage: wakeclaude shift <+1h|-30m> [--type <type>] [--tag <tag>] [--id <ids>] [--yes]")
+		return 2
+	}
+	delta, err := time.ParseDuration(strings.TrimPrefix(fs.Arg(0), "+"))
+	if err != nil || delta == 0 || delta%time.Minute != 0 {
+		fmt.Fprintf(os.Stderr, "invalid shift: %s (use whole minutes, e.g. +1h or -30m)\n", fs.Arg(0))
+		return 2
+	}
+
+	store, err := scheduler.DefaultStore()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	schedules, err := store.LoadSchedules()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	wanted := make(map[string]bool)
+	for _, id := range strings.Split(ids, ",") {
+		if id = strings.TrimSpace(id); id != "" {
+			wanted[id] = true
+		}
+	}
+	tag = strings.ToLower(strings.TrimLeft(strings.TrimSpace(tag), "#"))
+
+	now := time.Now()
+	var currents, shifted []scheduler.ScheduleEntry
+	failed := false
+	for _, entry := range schedules {
+		if len(wanted) > 0 && !wanted[entry.ID] {
+			continue
+		}
+		if scheduleType != "" && entry.Schedule.Type != scheduleType {
+			continue
+		}
+		if tag != "" && !hasTag(entry.Tags, tag) {
+			continue
+		}
+		if entry.Schedule.Type == "sun" || entry.Schedule.Type == "login" || entry.Schedule.Type == "cron" || entry.Schedule.Type == "interval" {
+			continue
+		}
+		next, err := shiftSchedule(entry, delta, now)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%s  %s: %v\n", entry.ID, scheduler.ScheduleLabel(entry), err)
+			failed = true
+			continue
+		}
+		currents = append(currents, entry)
+		shifted = append(shifted, next)
+	}
+	if failed {
+		fmt.Fprintln(os.Stderr, "Nothing changed; narrow the selection with --type, --tag or --id.")
+		return 1
+	}
+	if len(shifted) == 0 {
+		fmt.Println("No schedules to shift.")
+		return 0
+	}
+
+	fmt.Printf("Shift %d schedule(s) by %s:\n", len(shifted), fs.Arg(0))
+	for i := range shifted {
+		fmt.Printf("  %s  %s -> %s\n", shifted[i].ID, scheduler.ScheduleLabel(currents[i]), scheduler.ScheduleLabel(shifted[i]))
+	}
+	if !yes && !confirm("Apply?") {
+		fmt.Println("Cancelled.")
+		return 1
+	}
+
+	var commands []string
+	for i := range shifted {
+		commands = append(commands, scheduler.RemoveCommands(currents[i])...)
+		commands = append(commands, scheduler.InstallCommands(shifted[i])...)
+	}
+	if err := ensureSudoFor(commands); err != nil {
+		fmt.Fprintln(os.Stderr, sudoFailure(err, "sudo required to update wakeclaude"))
+		return 1
+	}
+	for i := range shifted {
+		if err := replaceSchedule(store, currents[i], shifted[i]); err != nil {
+			fmt.Fprintf(os.Stderr, "%s: %v\n", shifted[i].ID, err)
+			return 1
+		}
+	}
+	fmt.Printf("Shifted %d schedule(s).\n", len(shifted))
+	return 0
+}
+
+func shiftSchedule(entry scheduler.ScheduleEntry, delta time.Duration, now time.Time) (scheduler.ScheduleEntry, error) {
+	schedule := entry.Schedule
+	switch schedule.Type {
+	case "once":
+		loc := time.Local
+		if entry.Timezone != "" {
+			if location, err := time.LoadLocation(entry.Timezone); err == nil {
+				loc = location
+			}
+		}
+		at, err := time.ParseInLocation("2006-01-02 15:04", schedule.Date+" "+schedule.Time, loc)
+		if err != nil {
+			return entry, fmt.Errorf("invalid date/time")
+		}
+		at = at.Add(delta)
+		if !at.After(now) {
+			return entry, fmt.Errorf("would move into the past")
+		}
+		schedule.Date = at.Format("2006-01-02")
+		schedule.Time = at.Format("15:04")
+	case "daily", "weekly", "monthly":
+		times := scheduler.ScheduleTimes(schedule)
+		moved := make([]string, 0, len(times))
+		for _, clock := range times {
+			value, err := shiftClock(clock, delta)
+			if err != nil {
+				return entry, err
+			}
+			moved = append(moved, value)
+		}
+		if len(moved) == 0 {
+			return entry, fmt.Errorf("no time set")
+		}
+		moved, err := scheduler.ParseTimes(strings.Join(moved, ","))
+		if err != nil {
+			return entry, err
+		}
+		schedule.Time = moved[0]
+		schedule.Times = nil
+		if len(moved) > 1 {
+			schedule.Times = moved
+		}
+	default:
+		return entry, fmt.Errorf("schedule type %s has no fixed time", schedule.Type)
+	}
+
+	entry.Schedule = schedule
+	next, err := scheduler.NextRun(entry, now)
+	if err != nil {
+		return entry, err
+	}
+	entry.NextRun = next
+	entry.WakeTime = scheduler.FormatPMSet(next)
+	entry.UpdatedAt = now
+	return entry, nil
+}
+
+func shiftClock(clock string, delta time.Duration) (string, error) {
+	parsed, err := time.Parse("15:04", clock)
+	if err != nil {
+		return "", fmt.Errorf("invalid time: %s", clock)
+	}
+	minutes := parsed.Hour()*60 + parsed.Minute() + int(delta/time.Minute)
+	if minutes < 0 || minutes >= 24*60 {
+		return "", fmt.Errorf("%s would cross midnight", clock)
+	}
+	return fmt.Sprintf("%02d:%02d", minutes/60, minutes%60), nil
+}
+
+func hasTag(tags []string, tag string) bool {
+	for _, value := range tags {
+		if value == tag {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,131 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"wakeclaude/internal/app"
+	"wakeclaude/internal/scheduler"
+)
+
+func runStatus(args []string) int {
+	fs := flag.NewFlagSet("wakeclaude status", flag.ContinueOnError)
+	fs.SetOutput(os.Stderr)
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return 0
+		}
+		return 2
+	}
+
+	store, err := scheduler.DefaultStore()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	schedules, err := store.LoadSchedules()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	if console, err := scheduler.CurrentConsoleUser(); err == nil {
+		name := console.Username
+		if name == "" {
+			name = "(unknown)"
+		}
+		fmt.Printf("Console user: %s (uid %d)\n", name, console.UID)
+	} else {
+		fmt.Printf("Console user: unavailable (%v)\n", err)
+	}
+
+	fmt.Printf("Sudo: %s\n", sudoCacheLabel())
+
+	if len(schedules) == 0 {
+		fmt.Println("No schedules.")
+		return 0
+	}
+
+	now := time.Now()
+	for _, entry := range schedules {
+		fmt.Println()
+		fmt.Printf("%s  %s\n", entry.ID, scheduler.ScheduleLabel(entry))
+		if entry.Description != "" {
+			fmt.Printf("  Description: %s\n", entry.Description)
+		}
+		if entry.GroupID != "" {
+			fmt.Printf("  Group: %s\n", scheduler.GroupLabel(entry))
+		}
+		if entry.PreCommand != "" {
+			fmt.Printf("  Before: %s\n", entry.PreCommand)
+		}
+		if entry.PostCommand != "" {
+			fmt.Printf("  After: %s\n", entry.PostCommand)
+		}
+		if len(entry.Env) > 0 {
+			fmt.Printf("  Env: %s\n", strings.Join(scheduler.EnvKeys(entry.Env), ", "))
+		}
+		if len(entry.Tags) > 0 {
+			fmt.Printf("  Tags: %s\n", scheduler.FormatTags(entry.Tags))
+		}
+		if guard := scheduler.BatteryGuardLabel(entry); guard != "" {
+			fmt.Printf("  Battery: %s\n", guard)
+		}
+		if entry.LowPriority {
+			fmt.Println("  Priority: low (background)")
+		} else if processType := scheduler.ProcessTypeLabel(entry); processType != "" {
+			fmt.Printf("  Process type: %s\n", processType)
+		}
+		if entry.OutputFormat != "" {
+			fmt.Printf("  Output: %s\n", entry.OutputFormat)
+		}
+		if entry.RequireNetwork {
+			fmt.Printf("  Network: skip when %s is unreachable\n", scheduler.NetworkHostLabel(entry))
+		}
+		if entry.RequireDirty {
+			fmt.Println("  Condition: only with uncommitted changes")
+		}
+		if retries := scheduler.RetryLabel(entry); retries != "" {
+			fmt.Printf("  Retries: %s\n", retries)
+		}
+		if start := scheduler.StartDate(entry, time.Now()); start != "" {
+			fmt.Printf("  Starts: %s\n", start)
+		}
+		if entry.Notify != "" && entry.Notify != "always" {
+			fmt.Printf("  Notify: %s\n", entry.Notify)
+		}
+		if entry.NotifySound != "" {
+			fmt.Printf("  Notify sound: %s\n", entry.NotifySound)
+		}
+		if entry.Disabled {
+			fmt.Println("  Disabled: yes (launchd job off, no wake)")
+		}
+		fmt.Printf("  Project: %s\n", app.DisplayProjectPath(entry.ProjectPath))
+		fmt.Printf("  Runs in: %s\n", scheduler.WorkDirLabel(entry))
+		if entry.ClaudeBinary != "" {
+			fmt.Printf("  Claude: %s\n", app.HumanizePath(entry.ClaudeBinary))
+		}
+		if entry.PausedUntil.After(now) {
+			fmt.Printf("  Paused until: %s (%s)\n", entry.PausedUntil.Format(time.RFC1123), scheduler.RelativeLabel(entry.PausedUntil, now))
+		}
+		if !entry.NextRun.IsZero() {
+			fmt.Printf("  Next run: %s (%s)\n", scheduler.FormatInZone(entry, entry.NextRun, nextRunLayout), scheduler.RelativeLabel(entry.NextRun, now))
+		}
+		fmt.Printf("  Runs as: %s\n", runAsLabel(entry))
+		if warning := consoleUserWarning(entry); warning != "" {
+			fmt.Printf("  %s\n", warning)
+		}
+		account, warning := tokenAccountStatus(entry)
+		if account != "" {
+			fmt.Printf("  Token account: %s\n", account)
+		}
+		if warning != "" {
+			fmt.Printf("  %s\n", warning)
+		}
+	}
+	return 0
+}
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+
+	"wakeclaude/internal/scheduler"
+)
+
+func runSudoRefresh(args []string) int {
+	fs := flag.NewFlagSet("wakeclaude sudo-refresh", flag.ContinueOnError)
+	fs.SetOutput(os.Stderr)
+
+	var forget bool
+	fs.BoolVar(&forget, "clear", false, "Forget the cached sudo password instead")
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return 0
+		}
+		return 2
+	}
+
+	if forget {
+		if err := scheduler.ForgetSudo(); err != nil {
+			fmt.Fprintln(os.Stderr, fmt.Errorf("clear sudo cache: %w", err))
+			return 1
+		}
+		fmt.Printf("Sudo: %s\n", sudoCacheLabel())
+		return 0
+	}
+	if err := scheduler.EnsureSudo(); err != nil {
+		fmt.Fprintln(os.Stderr, "sudo refresh failed")
+		return 1
+	}
+	fmt.Printf("Sudo: %s\n", sudoCacheLabel())
+	return 0
+}
+
+// sudoCacheLabel says whether the next schedule change will ask for the
+// sudo password.
+func sudoCacheLabel() string {
+	if scheduler.SudoCached() {
+		return "cached (changes won't ask for a password until it expires)"
+	}
+	return "not cached (the next create, edit or delete asks for your password)"
+}
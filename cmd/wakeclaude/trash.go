@@ -0,0 +1,164 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"wakeclaude/internal/scheduler"
+	"wakeclaude/internal/tui"
+)
+
+func runTrash(args []string) int {
+	fs := flag.NewFlagSet("wakeclaude trash", flag.ContinueOnError)
+	fs.SetOutput(os.Stderr)
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return 0
+		}
+		return 2
+	}
+
+	store, err := scheduler.DefaultStore()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	now := time.Now()
+	entries, err := store.LoadTrash(now)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	if len(entries) == 0 {
+		fmt.Println("Trash is empty.")
+		return 0
+	}
+
+	for _, entry := range entries {
+		schedule := entry.Schedule
+		fmt.Println()
+		fmt.Printf("%s  %s\n", schedule.ID, scheduler.ScheduleLabel(schedule))
+		if schedule.Description != "" {
+			fmt.Printf("  Description: %s\n", schedule.Description)
+		}
+		fmt.Printf("  Prompt: %s\n", scheduler.Preview(schedule.Prompt, 80))
+		fmt.Printf("  Deleted: %s (%s)\n", entry.DeletedAt.Format(time.RFC1123), scheduler.RelativeLabel(entry.DeletedAt, now))
+	}
+	fmt.Println()
+	fmt.Printf("Restore one with `wakeclaude restore <id>`. Deleted schedules are kept for %d days.\n", int(scheduler.TrashRetention/(24*time.Hour)))
+	return 0
+}
+
+func runRestore(args []string) int {
+	fs := flag.NewFlagSet("wakeclaude restore", flag.ContinueOnError)
+	fs.SetOutput(os.Stderr)
+	var asJSON bool
+	fs.BoolVar(&asJSON, "json", false, "Print the restored schedule as json instead of a summary")
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return 0
+		}
+		return 2
+	}
+	if fs.NArg() != 1 {
+		fmt.Fprintln(os.Stderr, "usage: wakeclaude restore <id> [--json]")
+		return 2
+	}
+	id := fs.Arg(0)
+
+	release := holdStdout(asJSON)
+	defer release()
+	store, err := scheduler.DefaultStore()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	now := time.Now()
+	entries, err := store.LoadTrash(now)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	var trashed *scheduler.ScheduleEntry
+	for i := range entries {
+		if entries[i].Schedule.ID == id {
+			trashed = &entries[i].Schedule
+			break
+		}
+	}
+	if trashed == nil {
+		fmt.Fprintf(os.Stderr, "not in trash: %s\n", id)
+		return 1
+	}
+
+	schedules, err := store.LoadSchedules()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	if _, exists := findSchedule(schedules, id); exists {
+		fmt.Fprintf(os.Stderr, "schedule %s already exists\n", id)
+		return 1
+	}
+
+	// Rebuild machine fields (binary path, user, PATH, next run) for this machine.
+	entry, err := buildEntry(draftFromEntry(*trashed), trashed)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "cannot restore %s: %v\n", id, err)
+		return 1
+	}
+	if err := createSchedule(store, entry); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	if err := store.RemoveFromTrash(id, now); err != nil {
+		fmt.Fprintln(os.Stderr, "warning: failed to remove restored schedule from the trash:", err)
+	}
+	if asJSON {
+		release()
+		return printJSON(entry)
+	}
+	fmt.Println("Schedule restored.")
+	printScheduled(entry)
+	return 0
+}
+
+func draftFromEntry(entry scheduler.ScheduleEntry) *tui.Draft {
+	return &tui.Draft{
+		ProjectPath:  entry.ProjectPath,
+		SessionID:    entry.SessionID,
+		SessionPath:  entry.SessionPath,
+		NewSession:   entry.NewSession,
+		ForkSession:  entry.ForkSession,
+		Model:        entry.Model,
+		Permission:   entry.PermissionMode,
+		Prompt:       entry.Prompt,
+		PreCommand:   entry.PreCommand,
+		PostCommand:  entry.PostCommand,
+		Env:          entry.Env,
+		Description:  entry.Description,
+		Tags:         entry.Tags,
+		Group:        entry.GroupName,
+		Notify:       entry.Notify,
+		NotifySound:  entry.NotifySound,
+		ClaudeBinary: entry.ClaudeBinary,
+		Schedule: tui.Schedule{
+			Type:      entry.Schedule.Type,
+			Date:      entry.Schedule.Date,
+			Time:      entry.Schedule.Time,
+			Times:     entry.Schedule.Times,
+			Weekday:   entry.Schedule.Weekday,
+			Day:       entry.Schedule.Day,
+			Cron:      entry.Schedule.Cron,
+			Interval:  entry.Schedule.IntervalMinutes,
+			StartDate: scheduler.StartDate(entry, time.Now()),
+			Event:     entry.Schedule.Event,
+			Latitude:  entry.Schedule.Latitude,
+			Longitude: entry.Schedule.Longitude,
+			Timezone:  entry.Timezone,
+		},
+	}
+}
@@ -0,0 +1,86 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"wakeclaude/internal/app"
+)
+
+const noUpdateCheckEnv = "WAKECLAUDE_NO_UPDATE_CHECK"
+
+func runCheckUpdate(args []string) int {
+	fs := flag.NewFlagSet("wakeclaude check-update", flag.ContinueOnError)
+	fs.SetOutput(os.Stderr)
+
+	var onStart string
+	fs.StringVar(&onStart, "on-start", "", "Also check (at most daily) when the tui starts: on or off")
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return 0
+		}
+		return 2
+	}
+
+	if onStart != "" {
+		cfg, err := app.LoadConfig()
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+		switch strings.ToLower(onStart) {
+		case "on", "true":
+			cfg.CheckUpdatesOnStart = true
+		case "off", "false":
+			cfg.CheckUpdatesOnStart = false
+		default:
+			fmt.Fprintf(os.Stderr, "invalid --on-start value: %s (use on or off)\n", onStart)
+			return 2
+		}
+		if err := app.SaveConfig(cfg); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+		fmt.Printf("Startup update check: %s\n", strings.ToLower(onStart))
+		return 0
+	}
+
+	info, err := app.CheckForUpdate(version, 0)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	if info.Newer {
+		printUpdateHint(info)
+		return 0
+	}
+	if version == "dev" {
+		fmt.Printf("Latest release is %s; this is a development build (%s).\n", info.Latest, version)
+		return 0
+	}
+	fmt.Printf("wakeclaude %s is up to date.\n", version)
+	return 0
+}
+
+func startupUpdateCheck() *app.UpdateInfo {
+	if os.Getenv(noUpdateCheckEnv) != "" {
+		return nil
+	}
+	cfg, err := app.LoadConfig()
+	if err != nil || !cfg.CheckUpdatesOnStart {
+		return nil
+	}
+	info, err := app.CheckForUpdate(version, app.UpdateCheckInterval)
+	if err != nil || !info.Newer {
+		return nil
+	}
+	return &info
+}
+
+func printUpdateHint(info app.UpdateInfo) {
+	exe, _ := os.Executable()
+	fmt.Fprintf(os.Stderr, "wakeclaude %s is available (you have %s); %s\n", info.Latest, info.Current, app.UpgradeHint(exe))
+}
@@ -16,12 +16,17 @@ const ClaudeInstallCmd = "curl -fsSL https://claude.ai/install.sh | bash"
 const ClaudeSetupTokenCmd = "claude setup-token"
 const ClaudeOAuthService = "wakeclaude-claude-oauth"
 
+var ErrKeychainLocked = errors.New("login keychain is locked")
+
 func ClaudeAvailable() bool {
 	_, err := exec.LookPath("claude")
 	return err == nil
 }
 
 func LoadOAuthToken() (string, error) {
+	if useSecretTool() {
+		return loadSecretToolToken()
+	}
 	account := currentUsername()
 	if account != "" {
 		cmd := exec.Command("/usr/bin/security", "find-generic-password", "-s", ClaudeOAuthService, "-a", account, "-w")
@@ -32,6 +37,8 @@ func LoadOAuthToken() (string, error) {
 				return token, nil
 			}
 			return "", os.ErrNotExist
+		} else if IsKeychainLocked(err, "") {
+			return "", ErrKeychainLocked
 		} else if !isTokenNotFound(err) {
 			// fall through to try without account, but remember the error
 		}
@@ -44,6 +51,9 @@ func LoadOAuthToken() (string, error) {
 		if isTokenNotFound(err) {
 			return "", os.ErrNotExist
 		}
+		if IsKeychainLocked(err, "") {
+			return "", ErrKeychainLocked
+		}
 		return "", err
 	}
 	token := strings.TrimSpace(string(output))
@@ -58,6 +68,9 @@ func SaveOAuthToken(token string) error {
 	if token == "" {
 		return fmt.Errorf("token is empty")
 	}
+	if useSecretTool() {
+		return saveSecretToolToken(token)
+	}
 	args := []string{"add-generic-password", "-s", ClaudeOAuthService, "-w", token, "-U"}
 	if account := currentUsername(); account != "" {
 		args = append(args, "-a", account)
@@ -76,6 +89,48 @@ func SaveOAuthToken(token string) error {
 	return nil
 }
 
+// OAuthTokenAccount returns the keychain account the setup token is saved
+// under, preferring an item for account when there is one.
+func OAuthTokenAccount(account string) (string, error) {
+	if useSecretTool() {
+		return secretToolAccount(account)
+	}
+	if account != "" {
+		cmd := exec.Command("/usr/bin/security", "find-generic-password", "-s", ClaudeOAuthService, "-a", account)
+		cmd.Env = append(os.Environ(), "LANG=C")
+		if err := cmd.Run(); err == nil {
+			return account, nil
+		} else if IsKeychainLocked(err, "") {
+			return "", ErrKeychainLocked
+		}
+	}
+
+	cmd := exec.Command("/usr/bin/security", "find-generic-password", "-s", ClaudeOAuthService)
+	cmd.Env = append(os.Environ(), "LANG=C")
+	output, err := cmd.Output()
+	if err != nil {
+		if isTokenNotFound(err) {
+			return "", os.ErrNotExist
+		}
+		if IsKeychainLocked(err, "") {
+			return "", ErrKeychainLocked
+		}
+		return "", err
+	}
+	return parseKeychainAccount(string(output)), nil
+}
+
+func parseKeychainAccount(output string) string {
+	for _, line := range strings.Split(output, "\n") {
+		value, ok := strings.CutPrefix(strings.TrimSpace(line), `"acct"<blob>=`)
+		if !ok || value == "<NULL>" {
+			continue
+		}
+		return strings.Trim(value, `"`)
+	}
+	return ""
+}
+
 func currentUsername() string {
 	if usr, err := user.Current(); err == nil {
 		if usr.Username != "" {
@@ -100,13 +155,33 @@ func isTokenNotFound(err error) bool {
 	return status.ExitStatus() == 44
 }
 
-func VerifyOAuthToken(token string) error {
+// security exits 36 (errSecInteractionNotAllowed) when the keychain is locked
+// and it cannot prompt, e.g. from a launchd job right after boot.
+func IsKeychainLocked(err error, stderr string) bool {
+	var exitErr *exec.ExitError
+	if !errors.As(err, &exitErr) {
+		return false
+	}
+	if status, ok := exitErr.Sys().(syscall.WaitStatus); ok && status.ExitStatus() == 36 {
+		return true
+	}
+	msg := strings.ToLower(stderr + " " + string(exitErr.Stderr))
+	return strings.Contains(msg, "interaction is not allowed") || strings.Contains(msg, "keychain is locked")
+}
+
+// VerifyOAuthToken tries token with a small claude -p call. claudePath is the
+// binary to run; "" looks claude up in PATH.
+func VerifyOAuthToken(claudePath, token string) error {
 	token = strings.TrimSpace(token)
 	if token == "" {
 		return fmt.Errorf("token is empty")
 	}
-	if _, err := exec.LookPath("claude"); err != nil {
-		return fmt.Errorf("claude not found in PATH")
+	if claudePath == "" {
+		path, err := exec.LookPath("claude")
+		if err != nil {
+			return fmt.Errorf("claude not found in PATH")
+		}
+		claudePath = path
 	}
 
 	verifyDir, err := WakeClaudeVerifyDir()
@@ -117,7 +192,7 @@ func VerifyOAuthToken(token string) error {
 		return fmt.Errorf("create verify directory: %w", err)
 	}
 
-	cmd := exec.Command("claude", "-p", "ping", "--permission-mode", "plan", "--model", "haiku")
+	cmd := exec.Command(claudePath, "-p", "ping", "--permission-mode", "plan", "--model", "haiku")
 	cmd.Dir = verifyDir
 	cmd.Env = append(os.Environ(),
 		"CLAUDE_CODE_OAUTH_TOKEN="+token,
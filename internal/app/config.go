@@ -0,0 +1,82 @@
+package app
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+type Config struct {
+	CheckUpdatesOnStart   bool   `json:"checkUpdatesOnStart,omitempty"`
+	RawOutput             bool   `json:"rawOutput,omitempty"`
+	ExplainSudo           bool   `json:"explainSudo,omitempty"`
+	DefaultModel          string `json:"defaultModel,omitempty"`
+	DefaultPermissionMode string `json:"defaultPermissionMode,omitempty"`
+	DefaultTimezone       string `json:"defaultTimezone,omitempty"`
+	QuickDelete           bool   `json:"quickDelete,omitempty"`
+	// Unset keeps the defaults; 0 keeps every log.
+	MaxRunLogs    *int `json:"maxRunLogs,omitempty"`
+	MaxDaemonLogs *int `json:"maxDaemonLogs,omitempty"`
+	// Runs of each schedule kept past maxRunLogs; 0 turns the floor off.
+	MinRunLogsPerSchedule *int `json:"minRunLogsPerSchedule,omitempty"`
+}
+
+func ConfigPath() (string, error) {
+	base, err := WakeClaudeSupportDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(base, "config.json"), nil
+}
+
+func LoadConfig() (Config, error) {
+	path, err := ConfigPath()
+	if err != nil {
+		return Config{}, err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return Config{}, nil
+		}
+		return Config{}, fmt.Errorf("read config: %w", err)
+	}
+	var cfg Config
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return Config{}, fmt.Errorf("parse config: %w", err)
+	}
+	return cfg, nil
+}
+
+func SaveConfig(cfg Config) error {
+	path, err := ConfigPath()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("create data directory: %w", err)
+	}
+	data, err := json.MarshalIndent(cfg, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encode config: %w", err)
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return fmt.Errorf("write config: %w", err)
+	}
+	return os.Rename(tmp, path)
+}
+
+// ScheduleTimezone is the timezone new schedules get: the config's
+// defaultTimezone when it names a known zone, else the local one.
+func ScheduleTimezone() string {
+	cfg, err := LoadConfig()
+	if err == nil && cfg.DefaultTimezone != "" {
+		if _, err := time.LoadLocation(cfg.DefaultTimezone); err == nil {
+			return cfg.DefaultTimezone
+		}
+	}
+	return time.Now().Location().String()
+}
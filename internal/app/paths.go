@@ -4,11 +4,17 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"runtime"
 	"strings"
+	"sync"
 )
 
 const wakeClaudeAppName = "WakeClaude"
 
+// DataDirEnv moves WakeClaude's state out of Application Support, for
+// isolated test runs or separate profiles.
+const DataDirEnv = "WAKECLAUDE_DATA_DIR"
+
 func DefaultProjectsRoot() (string, error) {
 	home, err := os.UserHomeDir()
 	if err != nil {
@@ -19,11 +25,39 @@ func DefaultProjectsRoot() (string, error) {
 }
 
 func WakeClaudeSupportDir() (string, error) {
+	if dir := DataDirOverride(); dir != "" {
+		return dir, nil
+	}
 	home, err := os.UserHomeDir()
 	if err != nil {
 		return "", fmt.Errorf("resolve home directory: %w", err)
 	}
-	return filepath.Join(home, "Library", "Application Support", wakeClaudeAppName), nil
+	return SupportDirFor(home), nil
+}
+
+// SupportDirFor is the data directory of the user with this home, unless
+// WAKECLAUDE_DATA_DIR overrides it.
+func SupportDirFor(home string) string {
+	if dir := DataDirOverride(); dir != "" {
+		return dir
+	}
+	if runtime.GOOS == "linux" {
+		return filepath.Join(home, ".local", "share", "wakeclaude")
+	}
+	return filepath.Join(home, "Library", "Application Support", wakeClaudeAppName)
+}
+
+// DataDirOverride returns WAKECLAUDE_DATA_DIR as an absolute path, or "".
+func DataDirOverride() string {
+	dir := strings.TrimSpace(os.Getenv(DataDirEnv))
+	if dir == "" {
+		return ""
+	}
+	abs, err := NormalizePath(dir)
+	if err != nil {
+		return ""
+	}
+	return abs
 }
 
 func WakeClaudeVerifyDir() (string, error) {
@@ -58,11 +92,11 @@ func IsWakeClaudeInternalPath(path string) bool {
 	if err != nil {
 		return false
 	}
-	base, err = NormalizePath(base)
+	base, err = CanonicalPath(base)
 	if err != nil {
 		return false
 	}
-	candidate, err := NormalizePath(path)
+	candidate, err := CanonicalPath(path)
 	if err != nil {
 		return false
 	}
@@ -111,6 +145,39 @@ func NormalizePath(path string) (string, error) {
 	return filepath.Clean(abs), nil
 }
 
+// CanonicalPath is NormalizePath with symlinks resolved, for comparing paths.
+// When the path doesn't exist yet, its nearest existing parent is resolved.
+func CanonicalPath(path string) (string, error) {
+	normalized, err := NormalizePath(path)
+	if err != nil {
+		return "", err
+	}
+	dir, rest := normalized, ""
+	for {
+		if resolved, err := filepath.EvalSymlinks(dir); err == nil {
+			return filepath.Join(resolved, rest), nil
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return normalized, nil
+		}
+		rest = filepath.Join(filepath.Base(dir), rest)
+		dir = parent
+	}
+}
+
+func SamePath(a, b string) bool {
+	left, err := CanonicalPath(a)
+	if err != nil {
+		return false
+	}
+	right, err := CanonicalPath(b)
+	if err != nil {
+		return false
+	}
+	return left == right
+}
+
 func HumanizePath(path string) string {
 	home, err := os.UserHomeDir()
 	if err != nil || home == "" {
@@ -133,3 +200,82 @@ func HumanizePath(path string) string {
 
 	return clean
 }
+
+var projectCWDCache sync.Map
+
+// ProjectCWD maps a ~/.claude/projects/<encoded> dir back to the directory its
+// sessions ran in. Other paths, or ones whose sessions can't be read, come
+// back unchanged.
+func ProjectCWD(path string) string {
+	expanded, err := ExpandHome(path)
+	if err != nil || !isClaudeProjectDir(expanded) {
+		return path
+	}
+	if cached, ok := projectCWDCache.Load(expanded); ok {
+		return cached.(string)
+	}
+	cwd := expanded
+	if sessions, err := CollectSessions(expanded); err == nil {
+		if _, dir := resolveProjectDisplay(expanded, sessions); dir != "" {
+			cwd = dir
+		}
+	}
+	if cwd == expanded {
+		if dir, ok := DecodeProjectDirName(filepath.Base(expanded)); ok {
+			cwd = dir
+		}
+	}
+	projectCWDCache.Store(expanded, cwd)
+	return cwd
+}
+
+// DisplayProjectPath is how a project path is shown: decoded to its real
+// directory when possible, then humanized.
+func DisplayProjectPath(path string) string {
+	if strings.TrimSpace(path) == "" {
+		return ""
+	}
+	return HumanizePath(ProjectCWD(path))
+}
+
+func isClaudeProjectDir(path string) bool {
+	parent := filepath.Dir(filepath.Clean(path))
+	return filepath.Base(parent) == "projects" && filepath.Base(filepath.Dir(parent)) == ".claude"
+}
+
+// DecodeProjectDirName turns a ~/.claude/projects dir name such as
+// -Users-me-my-app back into /Users/me/my-app. Claude writes "/", "." and
+// other separators all as "-", so the pieces are matched against existing
+// directories; ok is false when no existing path fits.
+func DecodeProjectDirName(name string) (string, bool) {
+	if !strings.HasPrefix(name, "-") {
+		return "", false
+	}
+	return decodeProjectDir(string(os.PathSeparator), strings.Split(name[1:], "-"))
+}
+
+func decodeProjectDir(dir string, parts []string) (string, bool) {
+	if len(parts) == 0 {
+		return dir, true
+	}
+	for n := 1; n <= len(parts); n++ {
+		for _, sep := range []string{"-", ".", "_", " "} {
+			segment := strings.Join(parts[:n], sep)
+			// An empty piece is a dot that followed a slash (-Users-me--config).
+			if parts[0] == "" {
+				if n == 1 {
+					continue
+				}
+				segment = "." + strings.Join(parts[1:n], sep)
+			}
+			candidate := filepath.Join(dir, segment)
+			if info, err := os.Stat(candidate); err != nil || !info.IsDir() {
+				continue
+			}
+			if path, ok := decodeProjectDir(candidate, parts[n:]); ok {
+				return path, true
+			}
+		}
+	}
+	return "", false
+}
@@ -2,7 +2,9 @@ package app
 
 import (
 	"bufio"
+	"bytes"
 	"encoding/json"
+	"io"
 	"os"
 	"strings"
 )
@@ -86,6 +88,37 @@ func ExtractPreview(path string) (string, error) {
 	return "", nil
 }
 
+// CountLines counts the records in a session transcript without parsing them;
+// each line is roughly one message or tool call.
+func CountLines(path string) (int, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return 0, err
+	}
+	defer file.Close()
+
+	buf := make([]byte, 64*1024)
+	lines := 0
+	last := byte('\n')
+	for {
+		n, err := file.Read(buf)
+		if n > 0 {
+			lines += bytes.Count(buf[:n], []byte{'\n'})
+			last = buf[n-1]
+		}
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return lines, err
+		}
+	}
+	if last != '\n' {
+		lines++
+	}
+	return lines, nil
+}
+
 func ExtractFirstUserText(path string) (string, error) {
 	file, err := os.Open(path)
 	if err != nil {
@@ -127,6 +160,54 @@ func ExtractFirstUserText(path string) (string, error) {
 	return "", nil
 }
 
+// SearchSession reports whether any summary or message text in the session
+// contains query, ignoring case. Unlike the preview it reads the whole file.
+func SearchSession(path, query string) bool {
+	query = strings.ToLower(strings.TrimSpace(query))
+	if query == "" {
+		return false
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		return false
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), scannerMaxSize)
+
+	// Plain queries look the same inside the JSON, so lines without them can
+	// skip parsing; anything JSON would escape has to be checked decoded.
+	raw := isPlainASCII(query)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if raw && !strings.Contains(strings.ToLower(line), query) {
+			continue
+		}
+		rec, ok := parseRecord(line)
+		if !ok {
+			continue
+		}
+		text := rec.Summary
+		if isUserRecord(rec) || isAssistantRecord(rec) {
+			text = extractContentText(rec.MessageContent)
+		}
+		if strings.Contains(strings.ToLower(text), query) {
+			return true
+		}
+	}
+	return false
+}
+
+func isPlainASCII(text string) bool {
+	for _, r := range text {
+		if r < 0x20 || r > 0x7e || strings.ContainsRune(`"\<>&`, r) {
+			return false
+		}
+	}
+	return true
+}
+
 type record struct {
 	Type           string          `json:"type"`
 	Summary        string          `json:"summary"`
@@ -0,0 +1,114 @@
+package app
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+type previewCacheEntry struct {
+	ModTime time.Time `json:"modTime"`
+	Preview string    `json:"preview"`
+	Lines   int       `json:"lines,omitempty"`
+}
+
+type previewCache struct {
+	mu      sync.Mutex
+	once    sync.Once
+	entries map[string]previewCacheEntry
+	dirty   bool
+	saving  sync.Mutex
+}
+
+var sessionPreviews = &previewCache{}
+
+func previewCachePath() (string, error) {
+	base, err := WakeClaudeSupportDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(base, "preview-cache.json"), nil
+}
+
+func (c *previewCache) load() {
+	c.once.Do(func() {
+		c.entries = make(map[string]previewCacheEntry)
+		path, err := previewCachePath()
+		if err != nil {
+			return
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return
+		}
+		_ = json.Unmarshal(data, &c.entries)
+		if c.entries == nil {
+			c.entries = make(map[string]previewCacheEntry)
+		}
+	})
+}
+
+// Entries cached before line counts were stored have Lines == 0 and are
+// recomputed once.
+func (c *previewCache) get(path string, modTime time.Time) (previewCacheEntry, bool) {
+	c.load()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry, ok := c.entries[path]
+	if !ok || !entry.ModTime.Equal(modTime) || entry.Lines == 0 {
+		return previewCacheEntry{}, false
+	}
+	return entry, true
+}
+
+func (c *previewCache) put(path string, modTime time.Time, preview string, lines int) {
+	c.load()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries[path] = previewCacheEntry{ModTime: modTime, Preview: preview, Lines: lines}
+	c.dirty = true
+}
+
+// saveAsync writes the cache in the background so the TUI never waits on it;
+// stale entries for deleted sessions are dropped on the way out.
+func (c *previewCache) saveAsync() {
+	c.mu.Lock()
+	if !c.dirty {
+		c.mu.Unlock()
+		return
+	}
+	snapshot := make(map[string]previewCacheEntry, len(c.entries))
+	for path, entry := range c.entries {
+		snapshot[path] = entry
+	}
+	c.dirty = false
+	c.mu.Unlock()
+
+	go func() {
+		c.saving.Lock()
+		defer c.saving.Unlock()
+		for path := range snapshot {
+			if _, err := os.Stat(path); err != nil {
+				delete(snapshot, path)
+			}
+		}
+		path, err := previewCachePath()
+		if err != nil {
+			return
+		}
+		data, err := json.Marshal(snapshot)
+		if err != nil {
+			return
+		}
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			return
+		}
+		tmp := path + ".tmp"
+		if err := os.WriteFile(tmp, data, 0o644); err != nil {
+			return
+		}
+		_ = os.Rename(tmp, path)
+	}()
+}
@@ -1,12 +1,15 @@
 package app
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
 	"sort"
 )
 
+var ErrNoProjects = errors.New("no Claude projects yet")
+
 func DiscoverProjects(root string) ([]Project, error) {
 	var err error
 	if root == "" {
@@ -80,8 +83,11 @@ func DiscoverProjects(root string) ([]Project, error) {
 		return projects[i].LastModified.After(projects[j].LastModified)
 	})
 
-	if len(projects) == 0 && warnings > 0 {
-		return nil, fmt.Errorf("no readable project directories found under %s", root)
+	if len(projects) == 0 {
+		if warnings > 0 {
+			return nil, fmt.Errorf("no readable project directories found under %s (%d could not be read); check permissions or pass --projects-root", root, warnings)
+		}
+		return nil, fmt.Errorf("%w in %s; run a Claude session in a project first", ErrNoProjects, root)
 	}
 
 	return projects, nil
@@ -91,7 +97,11 @@ func resolveProjectDisplay(projectPath string, sessions []Session) (string, stri
 	for _, session := range sessions {
 		cwd, err := ExtractCWD(session.Path)
 		if err == nil && cwd != "" {
-			return HumanizePath(cwd), cwd
+			display := HumanizePath(cwd)
+			if canonical, err := CanonicalPath(cwd); err == nil && canonical != filepath.Clean(cwd) {
+				display = fmt.Sprintf("%s → %s", display, HumanizePath(canonical))
+			}
+			return display, cwd
 		}
 	}
 
@@ -0,0 +1,24 @@
+package app
+
+import "strings"
+
+var shellCommandNames = map[string]bool{
+	"git": true, "npm": true, "npx": true, "yarn": true, "pnpm": true, "bun": true,
+	"brew": true, "sudo": true, "cd": true, "ls": true, "rm": true, "cp": true,
+	"mv": true, "mkdir": true, "chmod": true, "curl": true, "wget": true,
+	"docker": true, "kubectl": true, "cargo": true, "pip": true, "pip3": true,
+	"python3": true, "bash": true, "sh": true, "zsh": true,
+}
+
+func LooksLikeShellCommand(prompt string) bool {
+	prompt = strings.TrimSpace(prompt)
+	if prompt == "" || strings.Contains(prompt, "\n") {
+		return false
+	}
+	prompt = strings.TrimSpace(strings.TrimPrefix(prompt, "$ "))
+	if strings.HasPrefix(prompt, "./") || strings.HasPrefix(prompt, "~/") || strings.HasPrefix(prompt, "/") {
+		return true
+	}
+	fields := strings.Fields(prompt)
+	return len(fields) > 0 && shellCommandNames[fields[0]]
+}
@@ -0,0 +1,78 @@
+package app
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+// On Linux the setup token lives in the Secret Service keyring (GNOME
+// Keyring, KWallet) through libsecret's secret-tool, under the same service
+// and account as the macOS keychain item.
+func useSecretTool() bool {
+	return runtime.GOOS == "linux"
+}
+
+func secretToolLookup(account string) (string, error) {
+	args := []string{"lookup", "service", ClaudeOAuthService}
+	if account != "" {
+		args = append(args, "account", account)
+	}
+	output, err := exec.Command("secret-tool", args...).Output()
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			// secret-tool exits 1 with no output when nothing matches.
+			return "", os.ErrNotExist
+		}
+		return "", fmt.Errorf("secret-tool: %w", err)
+	}
+	token := strings.TrimSpace(string(output))
+	if token == "" {
+		return "", os.ErrNotExist
+	}
+	return token, nil
+}
+
+func loadSecretToolToken() (string, error) {
+	if account := currentUsername(); account != "" {
+		if token, err := secretToolLookup(account); err == nil || !errors.Is(err, os.ErrNotExist) {
+			return token, err
+		}
+	}
+	return secretToolLookup("")
+}
+
+func saveSecretToolToken(token string) error {
+	args := []string{"store", "--label=wakeclaude setup token", "service", ClaudeOAuthService}
+	if account := currentUsername(); account != "" {
+		args = append(args, "account", account)
+	}
+	cmd := exec.Command("secret-tool", args...)
+	cmd.Stdin = strings.NewReader(token)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return fmt.Errorf("keyring: %s", msg)
+		}
+		return fmt.Errorf("keyring: %w", err)
+	}
+	return nil
+}
+
+func secretToolAccount(account string) (string, error) {
+	if account != "" {
+		if _, err := secretToolLookup(account); err == nil {
+			return account, nil
+		}
+	}
+	if _, err := secretToolLookup(""); err != nil {
+		return "", err
+	}
+	return "", nil
+}
@@ -78,6 +78,7 @@ func CollectSessions(projectPath string) ([]Session, error) {
 			ID:      id,
 			Path:    filepath.Join(projectPath, name),
 			ModTime: entryInfo.ModTime(),
+			Size:    entryInfo.Size(),
 		})
 	}
 
@@ -98,6 +99,7 @@ func CollectSessions(projectPath string) ([]Session, error) {
 type previewResult struct {
 	index   int
 	preview string
+	lines   int
 }
 
 func fillSessionPreviews(sessions []Session) {
@@ -105,6 +107,20 @@ func fillSessionPreviews(sessions []Session) {
 		return
 	}
 
+	pending := make([]int, 0, len(sessions))
+	for i := range sessions {
+		if cached, ok := sessionPreviews.get(sessions[i].Path, sessions[i].ModTime); ok {
+			sessions[i].Preview = cached.Preview
+			sessions[i].Lines = cached.Lines
+			continue
+		}
+		pending = append(pending, i)
+	}
+	if len(pending) == 0 {
+		return
+	}
+	defer sessionPreviews.saveAsync()
+
 	workers := runtime.GOMAXPROCS(0)
 	if workers < 2 {
 		workers = 2
@@ -114,7 +130,7 @@ func fillSessionPreviews(sessions []Session) {
 	}
 
 	jobs := make(chan int)
-	results := make(chan previewResult, len(sessions))
+	results := make(chan previewResult, len(pending))
 
 	var wg sync.WaitGroup
 	for i := 0; i < workers; i++ {
@@ -126,12 +142,13 @@ func fillSessionPreviews(sessions []Session) {
 				if err != nil {
 					preview = ""
 				}
-				results <- previewResult{index: idx, preview: preview}
+				lines, _ := CountLines(sessions[idx].Path)
+				results <- previewResult{index: idx, preview: preview, lines: lines}
 			}
 		}()
 	}
 
-	for i := range sessions {
+	for _, i := range pending {
 		jobs <- i
 	}
 	close(jobs)
@@ -141,5 +158,7 @@ func fillSessionPreviews(sessions []Session) {
 
 	for res := range results {
 		sessions[res.index].Preview = res.preview
+		sessions[res.index].Lines = res.lines
+		sessionPreviews.put(sessions[res.index].Path, sessions[res.index].ModTime, res.preview, res.lines)
 	}
 }
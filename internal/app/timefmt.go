@@ -5,6 +5,18 @@ import (
 	"time"
 )
 
+// FormatSize renders a byte count as e.g. "512 B", "48 KB" or "1.2 MB".
+func FormatSize(size int64) string {
+	switch {
+	case size < 1024:
+		return fmt.Sprintf("%d B", size)
+	case size < 1024*1024:
+		return fmt.Sprintf("%d KB", size/1024)
+	default:
+		return fmt.Sprintf("%.1f MB", float64(size)/(1024*1024))
+	}
+}
+
 func RelativeTime(t time.Time) string {
 	now := time.Now()
 	if t.After(now) {
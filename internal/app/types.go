@@ -17,6 +17,8 @@ type Session struct {
 	ModTime time.Time `json:"mod_time"`
 	RelTime string    `json:"rel_time"`
 	Preview string    `json:"preview"`
+	Size    int64     `json:"size"`
+	Lines   int       `json:"lines"`
 }
 
 type ModelOption struct {
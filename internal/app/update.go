@@ -0,0 +1,138 @@
+package app
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	ReleasesURL         = "https://github.com/rittikbasu/wakeclaude/releases/latest"
+	latestReleaseAPI    = "https://api.github.com/repos/rittikbasu/wakeclaude/releases/latest"
+	UpdateCheckInterval = 24 * time.Hour
+)
+
+type UpdateInfo struct {
+	Current string
+	Latest  string
+	Newer   bool
+}
+
+type updateCache struct {
+	CheckedAt time.Time `json:"checkedAt"`
+	Latest    string    `json:"latest"`
+}
+
+// CheckForUpdate reuses a cached answer younger than maxAge; pass 0 to always
+// query GitHub.
+func CheckForUpdate(current string, maxAge time.Duration) (UpdateInfo, error) {
+	info := UpdateInfo{Current: current}
+	cachePath, err := updateCachePath()
+	if err != nil {
+		return info, err
+	}
+
+	var cache updateCache
+	if data, err := os.ReadFile(cachePath); err == nil {
+		_ = json.Unmarshal(data, &cache)
+	}
+	if maxAge <= 0 || cache.Latest == "" || time.Since(cache.CheckedAt) > maxAge {
+		latest, err := fetchLatestRelease()
+		if err != nil {
+			return info, err
+		}
+		cache = updateCache{CheckedAt: time.Now(), Latest: latest}
+		if data, err := json.Marshal(cache); err == nil {
+			_ = os.MkdirAll(filepath.Dir(cachePath), 0o755)
+			_ = os.WriteFile(cachePath, data, 0o644)
+		}
+	}
+
+	info.Latest = cache.Latest
+	info.Newer = versionNewer(cache.Latest, current)
+	return info, nil
+}
+
+func UpgradeHint(executable string) string {
+	if strings.Contains(executable, "/Caskroom/") || strings.Contains(executable, "/homebrew/") {
+		return "brew upgrade --cask wakeclaude"
+	}
+	return fmt.Sprintf("download it from %s", ReleasesURL)
+}
+
+func updateCachePath() (string, error) {
+	base, err := WakeClaudeSupportDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(base, "update-check.json"), nil
+}
+
+func fetchLatestRelease() (string, error) {
+	client := &http.Client{Timeout: 5 * time.Second}
+	req, err := http.NewRequest(http.MethodGet, latestReleaseAPI, nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("check for updates: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("check for updates: github returned %s", resp.Status)
+	}
+	var release struct {
+		TagName string `json:"tag_name"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&release); err != nil {
+		return "", fmt.Errorf("check for updates: %w", err)
+	}
+	if release.TagName == "" {
+		return "", fmt.Errorf("check for updates: no release tag found")
+	}
+	return release.TagName, nil
+}
+
+func versionNewer(latest, current string) bool {
+	l, ok := parseVersion(latest)
+	if !ok {
+		return false
+	}
+	c, ok := parseVersion(current)
+	if !ok {
+		return false
+	}
+	for i := range l {
+		if l[i] != c[i] {
+			return l[i] > c[i]
+		}
+	}
+	return false
+}
+
+func parseVersion(value string) ([3]int, bool) {
+	var parts [3]int
+	value = strings.TrimPrefix(strings.TrimSpace(value), "v")
+	if i := strings.IndexAny(value, "-+"); i >= 0 {
+		value = value[:i]
+	}
+	fields := strings.Split(value, ".")
+	if len(fields) == 0 || len(fields) > 3 {
+		return parts, false
+	}
+	for i, field := range fields {
+		n, err := strconv.Atoi(field)
+		if err != nil {
+			return parts, false
+		}
+		parts[i] = n
+	}
+	return parts, true
+}
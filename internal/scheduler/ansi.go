@@ -0,0 +1,68 @@
+package scheduler
+
+import "io"
+
+const (
+	ansiText = iota
+	ansiEscape
+	ansiCSI
+	ansiOSC
+	ansiOSCEscape
+)
+
+// ansiStripper drops ANSI escape sequences from everything written through
+// it. State carries across writes so sequences split between chunks are
+// still removed.
+type ansiStripper struct {
+	w     io.Writer
+	state int
+}
+
+func newANSIStripper(w io.Writer) *ansiStripper {
+	return &ansiStripper{w: w}
+}
+
+func (s *ansiStripper) Write(p []byte) (int, error) {
+	out := make([]byte, 0, len(p))
+	for _, c := range p {
+		switch s.state {
+		case ansiText:
+			if c == 0x1b {
+				s.state = ansiEscape
+				continue
+			}
+			out = append(out, c)
+		case ansiEscape:
+			switch c {
+			case '[':
+				s.state = ansiCSI
+			case ']':
+				s.state = ansiOSC
+			default:
+				s.state = ansiText
+			}
+		case ansiCSI:
+			if c >= 0x40 && c <= 0x7e {
+				s.state = ansiText
+			}
+		case ansiOSC:
+			if c == 0x07 {
+				s.state = ansiText
+			} else if c == 0x1b {
+				s.state = ansiOSCEscape
+			}
+		case ansiOSCEscape:
+			if c == '\\' {
+				s.state = ansiText
+			} else {
+				s.state = ansiOSC
+			}
+		}
+	}
+	if len(out) > 0 {
+		if _, err := s.w.Write(out); err != nil {
+			return 0, err
+		}
+	}
+	return len(p), nil
+}
@@ -2,20 +2,46 @@ package scheduler
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
 	"os"
 	"os/exec"
 	"strconv"
 	"strings"
+	"time"
 
 	"wakeclaude/internal/app"
 )
 
+const (
+	keychainRetries    = 3
+	keychainRetryDelay = 20 * time.Second
+)
+
 func loadOAuthToken(entry ScheduleEntry) (string, error) {
+	var token string
+	var err error
+	for attempt := 0; ; attempt++ {
+		token, err = loadOAuthTokenOnce(entry)
+		if !errors.Is(err, app.ErrKeychainLocked) || attempt >= keychainRetries {
+			break
+		}
+		time.Sleep(keychainRetryDelay)
+	}
+	if errors.Is(err, app.ErrKeychainLocked) {
+		return "", fmt.Errorf("%w; log in to unlock it before the next run", app.ErrKeychainLocked)
+	}
+	return token, err
+}
+
+func loadOAuthTokenOnce(entry ScheduleEntry) (string, error) {
 	if os.Geteuid() == 0 && entry.UID > 0 {
 		return loadOAuthTokenAsUser(entry)
 	}
 	token, err := app.LoadOAuthToken()
+	if errors.Is(err, app.ErrKeychainLocked) {
+		return "", err
+	}
 	if err != nil {
 		return "", fmt.Errorf("missing setup token; run %s", app.ClaudeSetupTokenCmd)
 	}
@@ -48,6 +74,9 @@ func loadOAuthTokenAsUser(entry ScheduleEntry) (string, error) {
 	cmd.Stderr = &stderr
 	output, err := cmd.Output()
 	if err != nil {
+		if app.IsKeychainLocked(err, stderr.String()) {
+			return "", app.ErrKeychainLocked
+		}
 		return "", fmt.Errorf("missing setup token; run %s", app.ClaudeSetupTokenCmd)
 	}
 	token := strings.TrimSpace(string(output))
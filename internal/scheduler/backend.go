@@ -0,0 +1,47 @@
+package scheduler
+
+import "runtime"
+
+// Scheduler registers a schedule's job with the OS and wakes the machine
+// for its runs.
+type Scheduler interface {
+	Ensure(entry ScheduleEntry) error
+	Remove(entry ScheduleEntry) error
+	ScheduleWake(entry ScheduleEntry, when string) error
+	CancelWake(entry ScheduleEntry) error
+	// Rearm is Ensure from inside a run, for jobs that only hold their next
+	// run.
+	Rearm(entry ScheduleEntry) error
+}
+
+// Backend is launchd and pmset on macOS, systemd user timers and rtcwake on
+// Linux.
+var Backend Scheduler = newBackend(runtime.GOOS)
+
+func newBackend(goos string) Scheduler {
+	if goos == "linux" {
+		return systemdScheduler{}
+	}
+	return launchdScheduler{}
+}
+
+type launchdScheduler struct{}
+
+func (launchdScheduler) Ensure(entry ScheduleEntry) error { return EnsureLaunchd(entry) }
+
+func (launchdScheduler) Remove(entry ScheduleEntry) error { return RemoveLaunchd(entry) }
+
+func (launchdScheduler) ScheduleWake(entry ScheduleEntry, when string) error {
+	return pmsetScheduleWake(entry, when)
+}
+
+func (launchdScheduler) CancelWake(entry ScheduleEntry) error { return pmsetCancelWake(entry) }
+
+func (launchdScheduler) Rearm(entry ScheduleEntry) error { return rearmLaunchd(entry) }
+
+// usesSystemd is true when Backend manages systemd units instead of launchd
+// jobs; the sudo command lists and in-place toggles are launchd-only.
+func usesSystemd() bool {
+	_, ok := Backend.(systemdScheduler)
+	return ok
+}
@@ -0,0 +1,225 @@
+package scheduler
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// launchd gets one calendar interval per matching combination; past this
+// many it fires on the expression's minutes every hour instead and the run
+// checks the full expression itself.
+const maxCronIntervals = 200
+
+type cronSpec struct {
+	minutes  uint64
+	hours    uint64
+	days     uint64
+	months   uint64
+	weekdays uint64
+	anyDay   bool
+	anyWeek  bool
+}
+
+var cronMacros = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+var cronMonthNames = []string{"jan", "feb", "mar", "apr", "may", "jun", "jul", "aug", "sep", "oct", "nov", "dec"}
+var cronWeekdayNames = []string{"sun", "mon", "tue", "wed", "thu", "fri", "sat"}
+
+// ValidateCron checks a standard 5-field cron expression (minute hour
+// day-of-month month day-of-week) or one of the @daily-style macros.
+func ValidateCron(expr string) error {
+	_, err := parseCron(expr)
+	return err
+}
+
+func parseCron(expr string) (cronSpec, error) {
+	expr = strings.TrimSpace(expr)
+	if macro, ok := cronMacros[strings.ToLower(expr)]; ok {
+		expr = macro
+	}
+	fields := strings.Fields(expr)
+	if len(fields) != 5 {
+		return cronSpec{}, fmt.Errorf("cron needs 5 fields (minute hour day month weekday), got %d", len(fields))
+	}
+
+	var spec cronSpec
+	var err error
+	if spec.minutes, err = parseCronField(fields[0], 0, 59, nil); err != nil {
+		return cronSpec{}, fmt.Errorf("minute: %w", err)
+	}
+	if spec.hours, err = parseCronField(fields[1], 0, 23, nil); err != nil {
+		return cronSpec{}, fmt.Errorf("hour: %w", err)
+	}
+	if spec.days, err = parseCronField(fields[2], 1, 31, nil); err != nil {
+		return cronSpec{}, fmt.Errorf("day of month: %w", err)
+	}
+	if spec.months, err = parseCronField(fields[3], 1, 12, cronMonthNames); err != nil {
+		return cronSpec{}, fmt.Errorf("month: %w", err)
+	}
+	if spec.weekdays, err = parseCronField(fields[4], 0, 7, cronWeekdayNames); err != nil {
+		return cronSpec{}, fmt.Errorf("day of week: %w", err)
+	}
+	// 7 is Sunday too.
+	if spec.weekdays&(1<<7) != 0 {
+		spec.weekdays = spec.weekdays&^(1<<7) | 1
+	}
+	spec.anyDay = strings.HasPrefix(fields[2], "*")
+	spec.anyWeek = strings.HasPrefix(fields[4], "*")
+	return spec, nil
+}
+
+// names, when given, are the values from min onward (jan=1, sun=0).
+func parseCronField(field string, min, max int, names []string) (uint64, error) {
+	var bits uint64
+	for _, part := range strings.Split(field, ",") {
+		step := 1
+		if base, value, ok := strings.Cut(part, "/"); ok {
+			n, err := strconv.Atoi(value)
+			if err != nil || n < 1 {
+				return 0, fmt.Errorf("invalid step: %s", part)
+			}
+			part, step = base, n
+		}
+
+		lo, hi := min, max
+		switch {
+		case part == "*":
+		case strings.Contains(part, "-"):
+			start, end, _ := strings.Cut(part, "-")
+			var err error
+			if lo, err = cronValue(start, min, max, names); err != nil {
+				return 0, err
+			}
+			if hi, err = cronValue(end, min, max, names); err != nil {
+				return 0, err
+			}
+			if lo > hi {
+				return 0, fmt.Errorf("invalid range: %s", part)
+			}
+		default:
+			value, err := cronValue(part, min, max, names)
+			if err != nil {
+				return 0, err
+			}
+			lo = value
+			if step == 1 {
+				hi = value
+			}
+		}
+		for v := lo; v <= hi; v += step {
+			bits |= 1 << uint(v)
+		}
+	}
+	return bits, nil
+}
+
+func cronValue(value string, min, max int, names []string) (int, error) {
+	for i, name := range names {
+		if strings.EqualFold(value, name) {
+			return min + i, nil
+		}
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil || n < min || n > max {
+		return 0, fmt.Errorf("%q is not between %d and %d", value, min, max)
+	}
+	return n, nil
+}
+
+func (s cronSpec) matchesDay(t time.Time) bool {
+	day := s.days&(1<<uint(t.Day())) != 0
+	week := s.weekdays&(1<<uint(t.Weekday())) != 0
+	// Like cron: with both fields restricted, either one matching is enough.
+	if !s.anyDay && !s.anyWeek {
+		return day || week
+	}
+	return day && week
+}
+
+func (s cronSpec) matches(t time.Time) bool {
+	return s.minutes&(1<<uint(t.Minute())) != 0 &&
+		s.hours&(1<<uint(t.Hour())) != 0 &&
+		s.months&(1<<uint(t.Month())) != 0 &&
+		s.matchesDay(t)
+}
+
+// cronDue reports whether a launchd firing should run: the expression matches
+// now, or the planned run has passed (launchd fires missed runs after sleep).
+// The fallback intervals fire more often than the expression, so other
+// firings are ignored.
+func cronDue(entry ScheduleEntry, now time.Time) bool {
+	spec, err := parseCron(entry.Schedule.Cron)
+	if err != nil {
+		return false
+	}
+	if spec.matches(now.In(entryLocation(entry))) {
+		return true
+	}
+	return !entry.NextRun.IsZero() && !now.Before(entry.NextRun)
+}
+
+func cronIntervals(entry ScheduleEntry) ([]map[string]int, error) {
+	spec, err := parseCron(entry.Schedule.Cron)
+	if err != nil {
+		return nil, err
+	}
+
+	// A key left out of an interval matches every value.
+	base := []map[string]int{{}}
+	base = expandCron(base, "Minute", spec.minutes, 0, 59)
+	base = expandCron(base, "Hour", spec.hours, 0, 23)
+	base = expandCron(base, "Month", spec.months, 1, 12)
+
+	var intervals []map[string]int
+	switch {
+	case spec.anyDay && spec.anyWeek:
+		intervals = base
+	case spec.anyWeek:
+		intervals = expandCron(base, "Day", spec.days, 1, 31)
+	case spec.anyDay:
+		intervals = expandCron(base, "Weekday", spec.weekdays, 0, 6)
+	default:
+		// launchd treats Day and Weekday in separate intervals as either/or,
+		// which is what cron means when both are set.
+		intervals = append(expandCron(base, "Day", spec.days, 1, 31), expandCron(base, "Weekday", spec.weekdays, 0, 6)...)
+	}
+	if len(intervals) <= maxCronIntervals {
+		return intervals, nil
+	}
+	return expandCron([]map[string]int{{}}, "Minute", spec.minutes, 0, 59), nil
+}
+
+func expandCron(intervals []map[string]int, key string, bits uint64, min, max int) []map[string]int {
+	all := uint64(1<<uint(max+1)) - uint64(1<<uint(min))
+	if bits&all == all {
+		return intervals
+	}
+	var out []map[string]int
+	for _, interval := range intervals {
+		for v := min; v <= max; v++ {
+			if bits&(1<<uint(v)) == 0 {
+				continue
+			}
+			next := make(map[string]int, len(interval)+1)
+			for k, value := range interval {
+				next[k] = value
+			}
+			next[key] = v
+			out = append(out, next)
+			if len(out) > maxCronIntervals*2 {
+				return out
+			}
+		}
+	}
+	return out
+}
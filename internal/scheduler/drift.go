@@ -0,0 +1,44 @@
+package scheduler
+
+import (
+	"fmt"
+	"time"
+)
+
+const driftTimeLayout = "Mon, 02 Jan 2006 15:04"
+
+// ValidateSchedule reports a stored NextRun that no longer matches the
+// schedule, e.g. after the system timezone changed: launchd fires on the new
+// local times while NextRun and the wake still follow the old ones. A run
+// that is still going hasn't rescheduled yet, so a NextRun up to
+// MaxRetryWindow old is fine. While paused, NextRun still holds the runs that
+// will be skipped, so it isn't compared.
+func ValidateSchedule(entry ScheduleEntry, now time.Time) error {
+	if entry.Disabled || entry.Schedule.Type == "login" || entry.NextRun.IsZero() || entry.PausedUntil.After(now) {
+		return nil
+	}
+	if entry.NextRun.Before(now.Add(-MaxRetryWindow)) {
+		return fmt.Errorf("next run %s is in the past", FormatInZone(entry, entry.NextRun, driftTimeLayout))
+	}
+	if entry.NextRun.Before(now) {
+		return nil
+	}
+	expected, err := ResyncNextRun(entry, now)
+	if err != nil {
+		return err
+	}
+	if !expected.Equal(entry.NextRun) {
+		return fmt.Errorf("next run is %s but should be %s", FormatInZone(entry, entry.NextRun, driftTimeLayout), FormatInZone(entry, expected, driftTimeLayout))
+	}
+	return nil
+}
+
+// ResyncNextRun is the run entry should have next, counting from the end of
+// a pause like rescheduleNext does.
+func ResyncNextRun(entry ScheduleEntry, now time.Time) (time.Time, error) {
+	from := now
+	if from.Before(entry.PausedUntil) {
+		from = entry.PausedUntil
+	}
+	return NextRun(entry, from)
+}
@@ -0,0 +1,77 @@
+package scheduler
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+var envKeyPattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// reservedEnvKeys are set by the run itself so the setup token is the only
+// credential claude sees.
+var reservedEnvKeys = map[string]bool{
+	"CLAUDE_CODE_OAUTH_TOKEN": true,
+	"ANTHROPIC_API_KEY":       true,
+	"ANTHROPIC_AUTH_TOKEN":    true,
+}
+
+func ValidateEnvKey(key string) error {
+	if !envKeyPattern.MatchString(key) {
+		return fmt.Errorf("invalid variable name: %q", key)
+	}
+	if reservedEnvKeys[key] {
+		return fmt.Errorf("%s is set by wakeclaude and can't be overridden", key)
+	}
+	return nil
+}
+
+// ParseEnv reads KEY=VALUE lines; blank lines and # comments are skipped.
+func ParseEnv(text string) (map[string]string, error) {
+	env := make(map[string]string)
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			return nil, fmt.Errorf("expected KEY=VALUE: %q", line)
+		}
+		key = strings.TrimSpace(key)
+		if err := ValidateEnvKey(key); err != nil {
+			return nil, err
+		}
+		env[key] = strings.TrimSpace(value)
+	}
+	if len(env) == 0 {
+		return nil, nil
+	}
+	return env, nil
+}
+
+func FormatEnv(env map[string]string) string {
+	lines := make([]string, 0, len(env))
+	for _, key := range sortedKeys(env) {
+		lines = append(lines, key+"="+env[key])
+	}
+	return strings.Join(lines, "\n")
+}
+
+// envPairs skips keys that a hand-edited schedules file may have slipped past
+// ValidateEnvKey.
+func envPairs(env map[string]string) []string {
+	pairs := make([]string, 0, len(env))
+	for _, key := range sortedKeys(env) {
+		if ValidateEnvKey(key) != nil {
+			continue
+		}
+		pairs = append(pairs, key+"="+env[key])
+	}
+	return pairs
+}
+
+// EnvKeys lists the names only; values may be secrets.
+func EnvKeys(env map[string]string) []string {
+	return sortedKeys(env)
+}
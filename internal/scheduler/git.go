@@ -0,0 +1,41 @@
+package scheduler
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// dirtyTreeGuard returns why a run should be skipped, or "" to go ahead.
+// The run needs uncommitted changes in its work dir; a dir git can't read
+// is skipped too, so a misconfigured schedule shows up in the logs.
+func dirtyTreeGuard(entry ScheduleEntry) string {
+	if !entry.RequireDirty {
+		return ""
+	}
+	dir, _ := ResolveWorkDir(entry)
+	git, err := findInPath(entry.PathEnv, "git")
+	if err != nil {
+		return "git not found in PATH"
+	}
+	// The job may run as root: skip git's ownership check and don't let
+	// status rewrite the index, which would leave it owned by root.
+	cmd := exec.Command(git, "-c", "safe.directory=*", "-C", dir, "status", "--porcelain")
+	cmd.Env = append(os.Environ(), "GIT_OPTIONAL_LOCKS=0", "LANG=C")
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	output, err := cmd.Output()
+	if err != nil {
+		msg := strings.TrimSpace(stderr.String())
+		if msg == "" {
+			msg = err.Error()
+		}
+		return fmt.Sprintf("git status failed: %s", msg)
+	}
+	if strings.TrimSpace(string(output)) == "" {
+		return "clean tree"
+	}
+	return ""
+}
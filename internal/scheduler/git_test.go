@@ -0,0 +1,53 @@
+package scheduler
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestDirtyTreeGuard(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	repo := t.TempDir()
+	gitRun := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", append([]string{"-C", repo}, args...)...)
+		cmd.Env = append(os.Environ(), "GIT_AUTHOR_NAME=test", "GIT_AUTHOR_EMAIL=test@example.com",
+			"GIT_COMMITTER_NAME=test", "GIT_COMMITTER_EMAIL=test@example.com")
+		if output, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %s: %v\n%s", strings.Join(args, " "), err, output)
+		}
+	}
+	gitRun("init", "-q")
+	if err := os.WriteFile(filepath.Join(repo, "README"), []byte("hello\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	gitRun("add", "README")
+	gitRun("commit", "-q", "-m", "init")
+
+	entry := ScheduleEntry{RequireDirty: true, ProjectPath: repo, PathEnv: os.Getenv("PATH")}
+	if got := dirtyTreeGuard(entry); got != "clean tree" {
+		t.Errorf("clean tree: got %q, want %q", got, "clean tree")
+	}
+
+	if err := os.WriteFile(filepath.Join(repo, "README"), []byte("changed\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if got := dirtyTreeGuard(entry); got != "" {
+		t.Errorf("dirty tree: got %q, want the run to go ahead", got)
+	}
+
+	entry.ProjectPath = t.TempDir()
+	if got := dirtyTreeGuard(entry); !strings.HasPrefix(got, "git status failed: ") {
+		t.Errorf("not a repo: got %q, want a git status failure", got)
+	}
+
+	entry.RequireDirty = false
+	if got := dirtyTreeGuard(entry); got != "" {
+		t.Errorf("guard off: got %q, want the run to go ahead", got)
+	}
+}
@@ -0,0 +1,38 @@
+package scheduler
+
+import (
+	"strings"
+	"unicode"
+)
+
+// GroupSlug turns a group name like "Morning routine" into the id used to
+// match schedules and to filter the list with @morning-routine.
+func GroupSlug(name string) string {
+	fields := strings.FieldsFunc(strings.ToLower(name), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '_'
+	})
+	return strings.Join(fields, "-")
+}
+
+func GroupMembers(schedules []ScheduleEntry, groupID string) []ScheduleEntry {
+	if groupID == "" {
+		return nil
+	}
+	var members []ScheduleEntry
+	for _, entry := range schedules {
+		if entry.GroupID == groupID {
+			members = append(members, entry)
+		}
+	}
+	return members
+}
+
+func GroupLabel(entry ScheduleEntry) string {
+	if entry.GroupID == "" {
+		return ""
+	}
+	if entry.GroupName == "" {
+		return "@" + entry.GroupID
+	}
+	return entry.GroupName + " (@" + entry.GroupID + ")"
+}
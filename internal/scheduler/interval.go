@@ -0,0 +1,70 @@
+package scheduler
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+const minIntervalMinutes = 5
+
+// ParseInterval reads a repeat interval like 4h, 90m or 1h30m into minutes.
+func ParseInterval(value string) (int, error) {
+	value = strings.TrimSpace(value)
+	d, err := time.ParseDuration(value)
+	if err != nil || d%time.Minute != 0 {
+		return 0, fmt.Errorf("invalid interval: %s (use e.g. 4h or 90m)", value)
+	}
+	minutes := int(d / time.Minute)
+	if minutes < minIntervalMinutes {
+		return 0, fmt.Errorf("interval must be at least %dm", minIntervalMinutes)
+	}
+	return minutes, nil
+}
+
+func IntervalLabel(minutes int) string {
+	switch {
+	case minutes%60 != 0:
+		return fmt.Sprintf("%d minutes", minutes)
+	case minutes == 60:
+		return "hour"
+	default:
+		return fmt.Sprintf("%d hours", minutes/60)
+	}
+}
+
+// nextInterval counts whole intervals from the schedule's creation time, so
+// runs stay on the same grid after sleep or a skipped run. An anchor in the
+// future means the clock went back: within one interval it is still the
+// grid's next point, and further out (an NTP jump or a manual change) the
+// grid restarts from now rather than stalling until the clock catches up.
+func nextInterval(minutes int, anchor, now time.Time) (time.Time, error) {
+	if minutes < minIntervalMinutes {
+		return time.Time{}, fmt.Errorf("invalid interval: %d minutes", minutes)
+	}
+	every := time.Duration(minutes) * time.Minute
+	if anchor.After(now) && anchor.Sub(now) <= every {
+		return anchor.Truncate(time.Second), nil
+	}
+	if anchor.IsZero() || anchor.After(now) {
+		anchor = now
+	}
+	steps := now.Sub(anchor)/every + 1
+	return anchor.Add(steps * every).Truncate(time.Second), nil
+}
+
+// intervalSeconds is the StartInterval launchd gets for interval schedules,
+// or 0 for every other type.
+func intervalSeconds(entry ScheduleEntry) int {
+	if entry.Schedule.Type != "interval" {
+		return 0
+	}
+	return entry.Schedule.IntervalMinutes * 60
+}
+
+// launchd's StartInterval counts from when the job was loaded, not from the
+// schedule's grid, so a firing only runs once the planned run is (about) due.
+// Re-armed schedules use the same check.
+func intervalDue(entry ScheduleEntry, now time.Time) bool {
+	return entry.NextRun.IsZero() || !now.Add(time.Minute).Before(entry.NextRun)
+}
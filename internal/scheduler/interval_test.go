@@ -0,0 +1,36 @@
+package scheduler
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNextInterval(t *testing.T) {
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name    string
+		minutes int
+		anchor  time.Time
+		want    time.Time
+	}{
+		{"no anchor", 60, time.Time{}, now.Add(time.Hour)},
+		{"on the anchor's grid", 90, now.Add(-100 * time.Minute), now.Add(80 * time.Minute)},
+		{"anchor in the future", 60, now.Add(20 * time.Minute), now.Add(20 * time.Minute)},
+		{"anchor implausibly far ahead", 60, now.Add(72 * time.Hour), now.Add(time.Hour)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := nextInterval(tt.minutes, tt.anchor, now)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !got.Equal(tt.want) {
+				t.Errorf("got %s, want %s", got, tt.want)
+			}
+		})
+	}
+
+	if _, err := nextInterval(minIntervalMinutes-1, now, now); err == nil {
+		t.Error("accepted an interval below the minimum")
+	}
+}
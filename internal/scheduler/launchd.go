@@ -1,28 +1,210 @@
 package scheduler
 
 import (
+	"bytes"
 	"fmt"
 	"io"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"sort"
 	"strings"
+	"syscall"
 	"time"
+
+	"wakeclaude/internal/app"
+)
+
+const (
+	launchdDomain   = "system"
+	lowPriorityNice = 10
 )
 
-const launchdDomain = "system"
+// ProcessTypes are the launchd ProcessType values a schedule can ask for.
+// Left unset, launchd treats the job as Standard.
+var ProcessTypes = []string{"interactive", "standard", "background"}
+
+func ValidProcessType(value string) bool {
+	for _, known := range ProcessTypes {
+		if value == known {
+			return true
+		}
+	}
+	return false
+}
+
+// ProcessTypeLabel is the ProcessType written to the plist, or "" when the
+// key is left out. Low priority always runs as Background.
+func ProcessTypeLabel(entry ScheduleEntry) string {
+	if entry.LowPriority {
+		return "Background"
+	}
+	if !ValidProcessType(entry.ProcessType) {
+		return ""
+	}
+	return strings.ToUpper(entry.ProcessType[:1]) + entry.ProcessType[1:]
+}
 
 func LaunchdPath(id string) string {
 	return filepath.Join("/Library/LaunchDaemons", fmt.Sprintf("com.wakeclaude.%s.plist", id))
 }
 
+func LaunchAgentPath(entry ScheduleEntry) string {
+	return filepath.Join(entry.HomeDir, "Library", "LaunchAgents", fmt.Sprintf("com.wakeclaude.%s.plist", entry.ID))
+}
+
+// serviceTarget is the job as launchctl enable/disable names it.
+func serviceTarget(entry ScheduleEntry) string {
+	if entry.Schedule.Type == "login" {
+		return fmt.Sprintf("gui/%d/com.wakeclaude.%s", entry.UID, entry.ID)
+	}
+	return fmt.Sprintf("%s/com.wakeclaude.%s", launchdDomain, entry.ID)
+}
+
+// NeedsRoot reports whether installing or removing the entry's job needs
+// sudo. Login schedules are per-user launch agents and need no pmset wake.
+func NeedsRoot(entry ScheduleEntry) bool {
+	return entry.Schedule.Type != "login"
+}
+
+// InstallCommands lists what Backend.Ensure and ScheduleWake run with sudo
+// for entry, so it can be shown before the password prompt.
+func InstallCommands(entry ScheduleEntry) []string {
+	if usesSystemd() {
+		return systemdCommands(entry, true)
+	}
+	if !NeedsRoot(entry) || entry.Disabled {
+		return nil
+	}
+	dest := LaunchdPath(entry.ID)
+	commands := []string{
+		fmt.Sprintf("install -m 644 %s %s", tempPlistPath(entry.ID), dest),
+		fmt.Sprintf("launchctl bootout %s %s", launchdDomain, dest),
+		fmt.Sprintf("launchctl enable %s", serviceTarget(entry)),
+		fmt.Sprintf("launchctl bootstrap %s %s", launchdDomain, dest),
+	}
+	if entry.WakeTime != "" {
+		commands = append(commands, fmt.Sprintf("pmset schedule wakeorpoweron %q %s", entry.WakeTime, wakeOwner(entry.ID)))
+	}
+	return commands
+}
+
+// RemoveCommands is InstallCommands for RemoveLaunchd and CancelWake.
+func RemoveCommands(entry ScheduleEntry) []string {
+	if usesSystemd() {
+		return systemdCommands(entry, false)
+	}
+	if !NeedsRoot(entry) {
+		return nil
+	}
+	dest := LaunchdPath(entry.ID)
+	commands := []string{
+		fmt.Sprintf("launchctl bootout %s %s", launchdDomain, dest),
+		fmt.Sprintf("rm -f %s", dest),
+	}
+	if entry.WakeTime != "" {
+		commands = append(commands, fmt.Sprintf("pmset schedule cancel wakeorpoweron %q %s", entry.WakeTime, wakeOwner(entry.ID)))
+	}
+	return commands
+}
+
+// CanToggleInPlace reports whether SetLaunchdEnabled can switch entry to its
+// Disabled state without reinstalling: the job file must be there, and to
+// enable it must be exactly what EnsureLaunchd would write now (a sun or
+// one-time schedule's times may have moved on while it was off).
+func CanToggleInPlace(entry ScheduleEntry) bool {
+	if usesSystemd() {
+		return false
+	}
+	path := LaunchdPath(entry.ID)
+	if entry.Schedule.Type == "login" {
+		path = LaunchAgentPath(entry)
+	}
+	current, err := os.ReadFile(path)
+	if err != nil {
+		return false
+	}
+	if entry.Disabled {
+		return true
+	}
+	var intervals []map[string]int
+	if entry.Schedule.Type != "login" {
+		if intervals, err = calendarIntervals(entry); err != nil {
+			return false
+		}
+	}
+	return bytes.Equal(current, buildPlist(entry, intervals))
+}
+
+// ToggleCommands is InstallCommands for SetLaunchdEnabled.
+func ToggleCommands(entry ScheduleEntry) []string {
+	if !NeedsRoot(entry) {
+		return nil
+	}
+	dest := LaunchdPath(entry.ID)
+	if entry.Disabled {
+		commands := []string{
+			fmt.Sprintf("launchctl disable %s", serviceTarget(entry)),
+			fmt.Sprintf("launchctl bootout %s %s", launchdDomain, dest),
+		}
+		if entry.WakeTime != "" {
+			commands = append(commands, fmt.Sprintf("pmset schedule cancel wakeorpoweron %q %s", entry.WakeTime, wakeOwner(entry.ID)))
+		}
+		return commands
+	}
+	commands := []string{
+		fmt.Sprintf("launchctl enable %s", serviceTarget(entry)),
+		fmt.Sprintf("launchctl bootstrap %s %s", launchdDomain, dest),
+	}
+	if entry.WakeTime != "" {
+		commands = append(commands, fmt.Sprintf("pmset schedule wakeorpoweron %q %s", entry.WakeTime, wakeOwner(entry.ID)))
+	}
+	return commands
+}
+
+// SetLaunchdEnabled switches the entry's job off or on with launchctl
+// disable/enable, keeping its plist; disabling also unloads it. Check
+// CanToggleInPlace first, and reinstall if this fails.
+func SetLaunchdEnabled(entry ScheduleEntry, enabled bool) error {
+	verb := "disable"
+	if enabled {
+		verb = "enable"
+	}
+	if entry.Schedule.Type == "login" {
+		// Like ensureLaunchAgent, an enabled agent is left to load at login.
+		if err := exec.Command("launchctl", verb, serviceTarget(entry)).Run(); err != nil {
+			return fmt.Errorf("launchctl %s: %w", verb, err)
+		}
+		if !enabled {
+			_ = exec.Command("launchctl", "bootout", fmt.Sprintf("gui/%d", entry.UID), LaunchAgentPath(entry)).Run()
+		}
+		return nil
+	}
+
+	dest := LaunchdPath(entry.ID)
+	if err := runSudo("launchctl", verb, serviceTarget(entry)); err != nil {
+		return fmt.Errorf("launchctl %s: %w", verb, err)
+	}
+	if !enabled {
+		_ = runSudoQuiet("launchctl", "bootout", launchdDomain, dest)
+		return nil
+	}
+	if err := runSudo("launchctl", "bootstrap", launchdDomain, dest); err != nil {
+		return fmt.Errorf("load launchd job: %w", err)
+	}
+	return nil
+}
+
 func EnsureLaunchd(entry ScheduleEntry) error {
-	interval, err := calendarInterval(entry)
+	if entry.Schedule.Type == "login" {
+		return ensureLaunchAgent(entry)
+	}
+	intervals, err := calendarIntervals(entry)
 	if err != nil {
 		return err
 	}
 
-	plist := buildPlist(entry, interval)
+	plist := buildPlist(entry, intervals)
 	tmp, err := writeTempPlist(entry.ID, plist)
 	if err != nil {
 		return err
@@ -35,13 +217,35 @@ func EnsureLaunchd(entry ScheduleEntry) error {
 	}
 
 	_ = runSudoQuiet("launchctl", "bootout", launchdDomain, dest)
+	// Clears a disable left by SetLaunchdEnabled, which would block bootstrap.
+	_ = runSudoQuiet("launchctl", "enable", serviceTarget(entry))
 	if err := runSudo("launchctl", "bootstrap", launchdDomain, dest); err != nil {
 		return fmt.Errorf("load launchd job: %w", err)
 	}
 	return nil
 }
 
+// The agent is only written, not bootstrapped: RunAtLoad would fire it right
+// away, and launchd loads it from ~/Library/LaunchAgents at the next login.
+func ensureLaunchAgent(entry ScheduleEntry) error {
+	dest := LaunchAgentPath(entry)
+	if err := os.MkdirAll(filepath.Dir(dest), 0o755); err != nil {
+		return fmt.Errorf("create launch agents dir: %w", err)
+	}
+	if err := os.WriteFile(dest, buildPlist(entry, nil), 0o644); err != nil {
+		return fmt.Errorf("write launch agent: %w", err)
+	}
+	_ = exec.Command("launchctl", "enable", serviceTarget(entry)).Run()
+	return nil
+}
+
 func RemoveLaunchd(entry ScheduleEntry) error {
+	if entry.Schedule.Type == "login" {
+		dest := LaunchAgentPath(entry)
+		_ = exec.Command("launchctl", "bootout", fmt.Sprintf("gui/%d", entry.UID), dest).Run()
+		_ = os.Remove(dest)
+		return nil
+	}
 	dest := LaunchdPath(entry.ID)
 	_ = runSudoQuiet("launchctl", "bootout", launchdDomain, dest)
 	_ = runSudo("rm", "-f", dest)
@@ -57,52 +261,151 @@ func RemoveLaunchdIfRoot(entry ScheduleEntry) {
 	_ = runSudo("rm", "-f", dest)
 }
 
-func calendarInterval(entry ScheduleEntry) (map[string]int, error) {
+func calendarIntervals(entry ScheduleEntry) ([]map[string]int, error) {
 	switch entry.Schedule.Type {
 	case "once":
 		next, err := NextRun(entry, time.Now())
 		if err != nil {
 			return nil, err
 		}
-		return map[string]int{
+		return []map[string]int{{
 			"Year":   next.Year(),
 			"Month":  int(next.Month()),
 			"Day":    next.Day(),
 			"Hour":   next.Hour(),
 			"Minute": next.Minute(),
-		}, nil
+		}}, nil
 	case "daily":
-		hour, minute := parseClock(entry.Schedule.Time)
-		return map[string]int{
-			"Hour":   hour,
-			"Minute": minute,
-		}, nil
+		clocks := ScheduleTimes(entry.Schedule)
+		if len(clocks) == 0 {
+			clocks = []string{""}
+		}
+		intervals := make([]map[string]int, 0, len(clocks))
+		for _, clock := range clocks {
+			hour, minute := parseClock(clock)
+			intervals = append(intervals, map[string]int{
+				"Hour":   hour,
+				"Minute": minute,
+			})
+		}
+		return intervals, nil
 	case "weekly":
-		hour, minute := parseClock(entry.Schedule.Time)
 		weekday, ok := WeekdayNumber(entry.Schedule.Weekday)
 		if !ok {
 			return nil, fmt.Errorf("invalid weekday: %s", entry.Schedule.Weekday)
 		}
-		return map[string]int{
-			"Weekday": weekday,
-			"Hour":    hour,
-			"Minute":  minute,
-		}, nil
+		clocks := ScheduleTimes(entry.Schedule)
+		if len(clocks) == 0 {
+			clocks = []string{""}
+		}
+		intervals := make([]map[string]int, 0, len(clocks))
+		for _, clock := range clocks {
+			hour, minute := parseClock(clock)
+			intervals = append(intervals, map[string]int{
+				"Weekday": weekday,
+				"Hour":    hour,
+				"Minute":  minute,
+			})
+		}
+		return intervals, nil
+	case "monthly":
+		if rearmsEachRun(entry) {
+			return nextFiring(entry)
+		}
+		day := entry.Schedule.Day
+		if day < 1 || day > 31 {
+			return nil, fmt.Errorf("invalid day of month: %d", day)
+		}
+		hour, minute := parseClock(entry.Schedule.Time)
+		return []map[string]int{{
+			"Day":    day,
+			"Hour":   hour,
+			"Minute": minute,
+		}}, nil
+	case "cron":
+		return cronIntervals(entry)
+	case "interval":
+		// Uses StartInterval instead; see intervalSeconds.
+		return nil, nil
+	case "sun":
+		return nextFiring(entry)
 	default:
 		return nil, fmt.Errorf("unknown schedule type: %s", entry.Schedule.Type)
 	}
 }
 
+// rearmsEachRun reports schedules whose runs launchd can't express as a
+// repeating calendar interval: sun times, which shift daily, and a day past
+// the 28th (launchd skips months without it) or the month's last day. Their
+// job only holds the next run, and each run re-arms it with the one after;
+// see Rearm.
+func rearmsEachRun(entry ScheduleEntry) bool {
+	switch entry.Schedule.Type {
+	case "sun":
+		return true
+	case "monthly":
+		return entry.Schedule.Day > 28 || entry.Schedule.Day == LastDayOfMonth
+	default:
+		return false
+	}
+}
+
+// nextFiring is the single calendar interval of a re-armed job: the stored
+// NextRun while it's still ahead, so the job and NextRun agree.
+func nextFiring(entry ScheduleEntry) ([]map[string]int, error) {
+	now := time.Now()
+	next := entry.NextRun
+	if !next.After(now) {
+		var err error
+		if next, err = NextRun(entry, now); err != nil {
+			return nil, err
+		}
+	}
+	next = next.In(entryLocation(entry))
+	return []map[string]int{{
+		"Year":   next.Year(),
+		"Month":  int(next.Month()),
+		"Day":    next.Day(),
+		"Hour":   next.Hour(),
+		"Minute": next.Minute(),
+	}}, nil
+}
+
+// rearmLaunchd writes entry's job with its next firing. It runs from the job
+// itself, and booting a job out stops its process, so the reload is left to
+// a shell in its own session that waits for this run to exit.
+func rearmLaunchd(entry ScheduleEntry) error {
+	intervals, err := calendarIntervals(entry)
+	if err != nil {
+		return err
+	}
+	dest := LaunchdPath(entry.ID)
+	if err := os.WriteFile(dest, buildPlist(entry, intervals), 0o644); err != nil {
+		return fmt.Errorf("write launchd plist: %w", err)
+	}
+	script := fmt.Sprintf("while kill -0 %d 2>/dev/null; do sleep 1; done; launchctl bootout %s %s; launchctl bootstrap %s %s",
+		os.Getpid(), launchdDomain, dest, launchdDomain, dest)
+	cmd := exec.Command("/bin/sh", "-c", script)
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setsid: true}
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("re-arm launchd job: %w", err)
+	}
+	return cmd.Process.Release()
+}
+
+func tempPlistPath(id string) string {
+	return filepath.Join(os.TempDir(), fmt.Sprintf("wakeclaude-%s.plist", id))
+}
+
 func writeTempPlist(id string, data []byte) (string, error) {
-	name := fmt.Sprintf("wakeclaude-%s.plist", id)
-	path := filepath.Join(os.TempDir(), name)
+	path := tempPlistPath(id)
 	if err := os.WriteFile(path, data, 0o644); err != nil {
 		return "", err
 	}
 	return path, nil
 }
 
-func buildPlist(entry ScheduleEntry, interval map[string]int) []byte {
+func buildPlist(entry ScheduleEntry, intervals []map[string]int) []byte {
 	arguments := []string{entry.BinaryPath, "--run", entry.ID}
 	env := map[string]string{
 		"PATH":    entry.PathEnv,
@@ -110,6 +413,11 @@ func buildPlist(entry ScheduleEntry, interval map[string]int) []byte {
 		"USER":    entry.User,
 		"LOGNAME": entry.User,
 	}
+	// The run has to find the same store the schedule was saved in.
+	if dir := app.DataDirOverride(); dir != "" {
+		env[app.DataDirEnv] = dir
+	}
+	logsDir := filepath.Join(app.SupportDirFor(entry.HomeDir), "logs")
 
 	var b strings.Builder
 	b.WriteString(`<?xml version="1.0" encoding="UTF-8"?>` + "\n")
@@ -120,16 +428,34 @@ func buildPlist(entry ScheduleEntry, interval map[string]int) []byte {
 	writeString(&b, fmt.Sprintf("com.wakeclaude.%s", entry.ID))
 	writeKey(&b, "ProgramArguments")
 	writeArray(&b, arguments)
-	writeKey(&b, "StartCalendarInterval")
-	writeDict(&b, interval)
+	if seconds := intervalSeconds(entry); seconds > 0 {
+		writeKey(&b, "StartInterval")
+		writeInt(&b, seconds)
+	} else if len(intervals) == 1 {
+		writeKey(&b, "StartCalendarInterval")
+		writeDict(&b, intervals[0])
+	} else if len(intervals) > 1 {
+		writeKey(&b, "StartCalendarInterval")
+		writeDictArray(&b, intervals)
+	}
 	writeKey(&b, "StandardOutPath")
-	writeString(&b, filepath.Join(entry.HomeDir, "Library", "Application Support", appName, "logs", fmt.Sprintf("daemon-%s.out.log", entry.ID)))
+	writeString(&b, filepath.Join(logsDir, fmt.Sprintf("daemon-%s.out.log", entry.ID)))
 	writeKey(&b, "StandardErrorPath")
-	writeString(&b, filepath.Join(entry.HomeDir, "Library", "Application Support", appName, "logs", fmt.Sprintf("daemon-%s.err.log", entry.ID)))
+	writeString(&b, filepath.Join(logsDir, fmt.Sprintf("daemon-%s.err.log", entry.ID)))
 	writeKey(&b, "EnvironmentVariables")
 	writeStringDict(&b, env)
 	writeKey(&b, "RunAtLoad")
-	writeBool(&b, false)
+	writeBool(&b, entry.Schedule.Type == "login")
+	if processType := ProcessTypeLabel(entry); processType != "" {
+		writeKey(&b, "ProcessType")
+		writeString(&b, processType)
+	}
+	if entry.LowPriority {
+		writeKey(&b, "Nice")
+		writeInt(&b, lowPriorityNice)
+		writeKey(&b, "LowPriorityIO")
+		writeBool(&b, true)
+	}
 	b.WriteString("</dict>\n</plist>\n")
 	return []byte(b.String())
 }
@@ -142,6 +468,10 @@ func writeString(b *strings.Builder, value string) {
 	fmt.Fprintf(b, "<string>%s</string>\n", xmlEscape(value))
 }
 
+func writeInt(b *strings.Builder, value int) {
+	fmt.Fprintf(b, "<integer>%d</integer>\n", value)
+}
+
 func writeBool(b *strings.Builder, value bool) {
 	if value {
 		b.WriteString("<true/>\n")
@@ -158,24 +488,42 @@ func writeArray(b *strings.Builder, values []string) {
 	b.WriteString("</array>\n")
 }
 
+// Keys are sorted so the same entry always gives the same plist.
 func writeDict(b *strings.Builder, values map[string]int) {
 	b.WriteString("<dict>\n")
-	for key, value := range values {
+	for _, key := range sortedKeys(values) {
 		writeKey(b, key)
-		fmt.Fprintf(b, "<integer>%d</integer>\n", value)
+		writeInt(b, values[key])
 	}
 	b.WriteString("</dict>\n")
 }
 
+func writeDictArray(b *strings.Builder, values []map[string]int) {
+	b.WriteString("<array>\n")
+	for _, value := range values {
+		writeDict(b, value)
+	}
+	b.WriteString("</array>\n")
+}
+
 func writeStringDict(b *strings.Builder, values map[string]string) {
 	b.WriteString("<dict>\n")
-	for key, value := range values {
+	for _, key := range sortedKeys(values) {
 		writeKey(b, key)
-		writeString(b, value)
+		writeString(b, values[key])
 	}
 	b.WriteString("</dict>\n")
 }
 
+func sortedKeys[V any](values map[string]V) []string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
 func xmlEscape(value string) string {
 	replacer := strings.NewReplacer(
 		`&`, "&amp;",
@@ -0,0 +1,28 @@
+package scheduler
+
+import "strings"
+
+var KnownModels = []string{"auto", "opus", "sonnet", "haiku"}
+
+var modelAliases = map[string]string{
+	"":        "auto",
+	"default": "auto",
+}
+
+// NormalizeModel maps aliases onto the stored model value; ok is false when
+// claude is unlikely to accept it. Full model ids (claude-...) pass through.
+func NormalizeModel(value string) (string, bool) {
+	model := strings.ToLower(strings.TrimSpace(value))
+	if alias, ok := modelAliases[model]; ok {
+		model = alias
+	}
+	for _, known := range KnownModels {
+		if model == known {
+			return model, true
+		}
+	}
+	if strings.HasPrefix(model, "claude-") && len(model) > len("claude-") {
+		return model, true
+	}
+	return strings.TrimSpace(value), false
+}
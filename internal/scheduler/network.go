@@ -0,0 +1,43 @@
+package scheduler
+
+import (
+	"fmt"
+	"net"
+	"time"
+)
+
+const (
+	DefaultNetworkHost = "api.anthropic.com:443"
+	networkDialTimeout = 5 * time.Second
+	networkRetries     = 3
+	networkRetryDelay  = 5 * time.Second
+)
+
+func NetworkHostLabel(entry ScheduleEntry) string {
+	if entry.NetworkHost != "" {
+		return entry.NetworkHost
+	}
+	return DefaultNetworkHost
+}
+
+// networkGuard returns why a run should be skipped, or "" to go ahead.
+// Wi-Fi often takes a few seconds to come back after a wake, so it retries.
+func networkGuard(entry ScheduleEntry) string {
+	if !entry.RequireNetwork {
+		return ""
+	}
+	host := NetworkHostLabel(entry)
+	var err error
+	for attempt := 0; attempt < networkRetries; attempt++ {
+		if attempt > 0 {
+			time.Sleep(networkRetryDelay)
+		}
+		var conn net.Conn
+		conn, err = net.DialTimeout("tcp", host, networkDialTimeout)
+		if err == nil {
+			conn.Close()
+			return ""
+		}
+	}
+	return fmt.Sprintf("offline (%s unreachable: %v)", host, err)
+}
@@ -2,20 +2,55 @@ package scheduler
 
 import (
 	"fmt"
+	"net/url"
 	"os"
 	"os/exec"
+	"runtime"
 	"strconv"
 	"strings"
 )
 
+// NotifyModes are the per-schedule notification settings; empty means always.
+var NotifyModes = []string{"always", "failure", "never"}
+
+func ValidNotifyMode(mode string) bool {
+	for _, value := range NotifyModes {
+		if value == mode {
+			return true
+		}
+	}
+	return mode == ""
+}
+
+func ShouldNotify(entry ScheduleEntry, logEntry LogEntry) bool {
+	switch entry.Notify {
+	case "never":
+		return false
+	case "failure":
+		return logEntry.Status != "success"
+	default:
+		return true
+	}
+}
+
 func NotifyRun(entry ScheduleEntry, logEntry LogEntry) {
-	script := buildNotificationScript(logEntry)
-	if script == "" {
+	if runtime.GOOS == "linux" {
+		// A systemd user service can reach the session's notification daemon.
+		title, subtitle, message := notificationText(entry, logEntry)
+		_ = exec.Command("notify-send", "--app-name=wakeclaude", title, subtitle+"\n"+message).Run()
 		return
 	}
+	args := []string{"/usr/bin/osascript", "-e", buildNotificationScript(entry, logEntry)}
+	// osascript notifications can't open anything when clicked;
+	// terminal-notifier can, so a failure opens its output when it's there.
+	if logEntry.Status != "success" && logEntry.OutputPath != "" {
+		if notifier, err := findInPath(entry.PathEnv, "terminal-notifier"); err == nil {
+			args = terminalNotifierArgs(notifier, entry, logEntry)
+		}
+	}
 
 	if os.Geteuid() == 0 && entry.UID > 0 {
-		cmd := exec.Command("/bin/launchctl", "asuser", strconv.Itoa(entry.UID), "/usr/bin/osascript", "-e", script)
+		cmd := exec.Command("/bin/launchctl", append([]string{"asuser", strconv.Itoa(entry.UID)}, args...)...)
 		cmd.Env = append(os.Environ(), []string{
 			"HOME=" + entry.HomeDir,
 			"USER=" + entry.User,
@@ -25,16 +60,70 @@ func NotifyRun(entry ScheduleEntry, logEntry LogEntry) {
 		return
 	}
 
-	cmd := exec.Command("/usr/bin/osascript", "-e", script)
+	cmd := exec.Command(args[0], args[1:]...)
 	_ = cmd.Run()
 }
 
-func buildNotificationScript(logEntry LogEntry) string {
-	title := "WakeClaude"
-	subtitle := "Run complete"
-	message := logEntry.PromptPreview
+func terminalNotifierArgs(notifier string, entry ScheduleEntry, logEntry LogEntry) []string {
+	title, subtitle, message := notificationText(entry, logEntry)
+	args := []string{
+		notifier,
+		"-title", title,
+		"-subtitle", subtitle,
+		"-message", message,
+		"-open", (&url.URL{Scheme: "file", Path: logEntry.OutputPath}).String(),
+		"-group", wakeOwner(entry.ID),
+	}
+	if sound := notificationSound(entry, logEntry); sound != "" {
+		args = append(args, "-sound", sound)
+	}
+	return args
+}
 
-	if logEntry.Status != "success" {
+// notificationSound is the schedule's NotifySound, Glass or Basso by
+// outcome when unset, and "" for "none".
+func notificationSound(entry ScheduleEntry, logEntry LogEntry) string {
+	switch sound := strings.TrimSpace(entry.NotifySound); sound {
+	case "none":
+		return ""
+	case "":
+		if logEntry.Status == "success" {
+			return "Glass"
+		}
+		return "Basso"
+	default:
+		return sound
+	}
+}
+
+func buildNotificationScript(entry ScheduleEntry, logEntry LogEntry) string {
+	title, subtitle, message := notificationText(entry, logEntry)
+	script := fmt.Sprintf(
+		`display notification "%s" with title "%s" subtitle "%s"`,
+		escapeAppleScript(message),
+		escapeAppleScript(title),
+		escapeAppleScript(subtitle),
+	)
+	if sound := notificationSound(entry, logEntry); sound != "" {
+		script += fmt.Sprintf(` sound name "%s"`, escapeAppleScript(sound))
+	}
+	return script
+}
+
+func notificationText(entry ScheduleEntry, logEntry LogEntry) (title, subtitle, message string) {
+	title = "WakeClaude"
+	if description := strings.TrimSpace(entry.Description); description != "" {
+		title = truncateNotification(description, 60)
+	}
+	subtitle = "Run complete"
+	message = logEntry.PromptPreview
+	if logEntry.ResultSummary != "" {
+		message = logEntry.ResultSummary
+	}
+
+	if logEntry.Status == "cancelled" {
+		subtitle = "Run cancelled"
+	} else if logEntry.Status != "success" {
 		subtitle = "Run failed"
 		if isMeaningfulError(logEntry.Error) {
 			message = logEntry.Error
@@ -49,14 +138,7 @@ func buildNotificationScript(logEntry LogEntry) string {
 		}
 	}
 
-	message = truncateNotification(message, 140)
-
-	return fmt.Sprintf(
-		`display notification "%s" with title "%s" subtitle "%s"`,
-		escapeAppleScript(message),
-		escapeAppleScript(title),
-		escapeAppleScript(subtitle),
-	)
+	return title, subtitle, truncateNotification(message, 140)
 }
 
 func isMeaningfulError(err string) bool {
@@ -4,10 +4,8 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
-)
 
-const (
-	appName = "WakeClaude"
+	"wakeclaude/internal/app"
 )
 
 type Store struct {
@@ -16,21 +14,21 @@ type Store struct {
 	LogsDir      string
 	Schedules    string
 	Logs         string
+	Trash        string
 }
 
 func DefaultStore() (*Store, error) {
-	home, err := os.UserHomeDir()
+	base, err := app.WakeClaudeSupportDir()
 	if err != nil {
-		return nil, fmt.Errorf("resolve home directory: %w", err)
+		return nil, err
 	}
-
-	base := filepath.Join(home, "Library", "Application Support", appName)
 	return &Store{
 		BaseDir:      base,
 		SchedulesDir: base,
 		LogsDir:      filepath.Join(base, "logs"),
 		Schedules:    filepath.Join(base, "schedules.json"),
 		Logs:         filepath.Join(base, "logs.jsonl"),
+		Trash:        filepath.Join(base, "trash.jsonl"),
 	}, nil
 }
 
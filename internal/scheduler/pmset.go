@@ -4,9 +4,12 @@ import (
 	"fmt"
 	"os"
 	"os/exec"
+	"regexp"
+	"strconv"
+	"strings"
 )
 
-func ScheduleWake(entry ScheduleEntry, when string) error {
+func pmsetScheduleWake(entry ScheduleEntry, when string) error {
 	if when == "" {
 		return nil
 	}
@@ -14,7 +17,7 @@ func ScheduleWake(entry ScheduleEntry, when string) error {
 	return runSudo("pmset", "schedule", "wakeorpoweron", when, owner)
 }
 
-func CancelWake(entry ScheduleEntry) error {
+func pmsetCancelWake(entry ScheduleEntry) error {
 	if entry.WakeTime == "" {
 		return nil
 	}
@@ -22,6 +25,82 @@ func CancelWake(entry ScheduleEntry) error {
 	return runSudo("pmset", "schedule", "cancel", "wakeorpoweron", entry.WakeTime, owner)
 }
 
+type BatteryStatus struct {
+	OnAC       bool
+	HasBattery bool
+	Percent    int
+}
+
+var batteryPercentPattern = regexp.MustCompile(`(\d{1,3})%`)
+
+func ReadBattery() (BatteryStatus, error) {
+	out, err := exec.Command("pmset", "-g", "batt").Output()
+	if err != nil {
+		return BatteryStatus{}, fmt.Errorf("pmset -g batt: %w", err)
+	}
+	return parseBatteryStatus(string(out)), nil
+}
+
+// parseBatteryStatus reads `pmset -g batt` output, e.g.
+//
+//	Now drawing from 'Battery Power'
+//	 -InternalBattery-0 (id=4653155)	82%; discharging; 5:02 remaining present: true
+func parseBatteryStatus(output string) BatteryStatus {
+	status := BatteryStatus{OnAC: true}
+	for _, line := range strings.Split(output, "\n") {
+		if strings.Contains(line, "drawing from") {
+			status.OnAC = !strings.Contains(line, "'Battery Power'")
+			continue
+		}
+		if !strings.Contains(line, "InternalBattery") {
+			continue
+		}
+		if match := batteryPercentPattern.FindStringSubmatch(line); match != nil {
+			if percent, err := strconv.Atoi(match[1]); err == nil {
+				status.HasBattery = true
+				status.Percent = percent
+			}
+		}
+	}
+	return status
+}
+
+// batteryGuard returns why a run should be skipped, or "" to go ahead.
+// If the battery can't be read the run goes ahead.
+func batteryGuard(entry ScheduleEntry) string {
+	if entry.MinBatteryPercent <= 0 && !entry.RequireAC {
+		return ""
+	}
+	status, err := ReadBattery()
+	if err != nil {
+		return ""
+	}
+	return batterySkipReason(entry, status)
+}
+
+func batterySkipReason(entry ScheduleEntry, status BatteryStatus) string {
+	if !status.HasBattery || status.OnAC {
+		return ""
+	}
+	if entry.RequireAC {
+		return fmt.Sprintf("on battery power (%d%%)", status.Percent)
+	}
+	if status.Percent < entry.MinBatteryPercent {
+		return fmt.Sprintf("low battery (%d%%, minimum %d%%)", status.Percent, entry.MinBatteryPercent)
+	}
+	return ""
+}
+
+func BatteryGuardLabel(entry ScheduleEntry) string {
+	if entry.RequireAC {
+		return "only on AC power"
+	}
+	if entry.MinBatteryPercent > 0 {
+		return fmt.Sprintf("skip below %d%% on battery", entry.MinBatteryPercent)
+	}
+	return ""
+}
+
 func wakeOwner(id string) string {
 	return fmt.Sprintf("com.wakeclaude.%s", id)
 }
@@ -0,0 +1,68 @@
+package scheduler
+
+import "testing"
+
+func TestParseBatteryStatus(t *testing.T) {
+	tests := []struct {
+		name   string
+		output string
+		want   BatteryStatus
+	}{
+		{
+			name: "on AC",
+			output: "Now drawing from 'AC Power'\n" +
+				" -InternalBattery-0 (id=4653155)\t100%; charged; 0:00 remaining present: true\n",
+			want: BatteryStatus{OnAC: true, HasBattery: true, Percent: 100},
+		},
+		{
+			name: "on battery",
+			output: "Now drawing from 'Battery Power'\n" +
+				" -InternalBattery-0 (id=4653155)\t82%; discharging; 5:02 remaining present: true\n",
+			want: BatteryStatus{OnAC: false, HasBattery: true, Percent: 82},
+		},
+		{
+			name:   "no battery",
+			output: "Now drawing from 'AC Power'\n",
+			want:   BatteryStatus{OnAC: true},
+		},
+		{
+			name:   "no output",
+			output: "",
+			want:   BatteryStatus{OnAC: true},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parseBatteryStatus(tt.output); got != tt.want {
+				t.Errorf("got %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBatterySkipReason(t *testing.T) {
+	onBattery := func(percent int) BatteryStatus {
+		return BatteryStatus{HasBattery: true, Percent: percent}
+	}
+	tests := []struct {
+		name   string
+		entry  ScheduleEntry
+		status BatteryStatus
+		want   string
+	}{
+		{"below threshold", ScheduleEntry{MinBatteryPercent: 30}, onBattery(29), "low battery (29%, minimum 30%)"},
+		{"at threshold", ScheduleEntry{MinBatteryPercent: 30}, onBattery(30), ""},
+		{"above threshold", ScheduleEntry{MinBatteryPercent: 30}, onBattery(80), ""},
+		{"below threshold on AC", ScheduleEntry{MinBatteryPercent: 30}, BatteryStatus{OnAC: true, HasBattery: true, Percent: 5}, ""},
+		{"require AC on battery", ScheduleEntry{RequireAC: true}, onBattery(100), "on battery power (100%)"},
+		{"require AC on AC", ScheduleEntry{RequireAC: true}, BatteryStatus{OnAC: true, HasBattery: true, Percent: 40}, ""},
+		{"require AC without a battery", ScheduleEntry{RequireAC: true}, BatteryStatus{OnAC: true}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := batterySkipReason(tt.entry, tt.status); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -1,10 +1,19 @@
 package scheduler
 
 import (
+	"fmt"
 	"os"
 	"os/exec"
+	"os/user"
+	"strconv"
+	"syscall"
 )
 
+type ConsoleUser struct {
+	Username string
+	UID      int
+}
+
 func EnsureSudo() error {
 	cmd := exec.Command("sudo", "-v")
 	cmd.Stdin = os.Stdin
@@ -12,3 +21,45 @@ func EnsureSudo() error {
 	cmd.Stderr = os.Stderr
 	return cmd.Run()
 }
+
+// SudoCached reports whether sudo would run without asking for a password
+// right now: running as root, a cached timestamp, or NOPASSWD.
+func SudoCached() bool {
+	if os.Geteuid() == 0 {
+		return true
+	}
+	return exec.Command("sudo", "-n", "-v").Run() == nil
+}
+
+// ForgetSudo drops the cached sudo timestamp, like sudo -k.
+func ForgetSudo() error {
+	return exec.Command("sudo", "-k").Run()
+}
+
+func CurrentConsoleUser() (ConsoleUser, error) {
+	info, err := os.Stat("/dev/console")
+	if err != nil {
+		return ConsoleUser{}, fmt.Errorf("stat /dev/console: %w", err)
+	}
+	stat, ok := info.Sys().(*syscall.Stat_t)
+	if !ok {
+		return ConsoleUser{}, fmt.Errorf("stat /dev/console: unsupported platform")
+	}
+	uid := int(stat.Uid)
+	console := ConsoleUser{UID: uid}
+	if usr, err := user.LookupId(strconv.Itoa(uid)); err == nil {
+		console.Username = usr.Username
+	}
+	return console, nil
+}
+
+func ConsoleUserMismatch(entry ScheduleEntry) (ConsoleUser, bool) {
+	console, err := CurrentConsoleUser()
+	if err != nil || console.UID == 0 {
+		return ConsoleUser{}, false
+	}
+	if console.UID == entry.UID {
+		return console, false
+	}
+	return console, true
+}
@@ -0,0 +1,60 @@
+package scheduler
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+)
+
+const resultSummaryLimit = 500
+
+// claudeResult is the final object `claude -p --output-format json` prints.
+type claudeResult struct {
+	Type      string      `json:"type"`
+	Subtype   string      `json:"subtype"`
+	IsError   bool        `json:"is_error"`
+	Result    string      `json:"result"`
+	SessionID string      `json:"session_id"`
+	CostUSD   float64     `json:"total_cost_usd"`
+	Usage     claudeUsage `json:"usage"`
+}
+
+type claudeUsage struct {
+	InputTokens         int `json:"input_tokens"`
+	OutputTokens        int `json:"output_tokens"`
+	CacheCreationTokens int `json:"cache_creation_input_tokens"`
+	CacheReadTokens     int `json:"cache_read_input_tokens"`
+}
+
+// parseClaudeResult finds the last JSON result object in the output; ok is
+// false when there isn't one, and the run is judged on its exit code alone.
+func parseClaudeResult(output []byte) (claudeResult, bool) {
+	lines := bytes.Split(bytes.TrimSpace(output), []byte("\n"))
+	for i := len(lines) - 1; i >= 0; i-- {
+		line := bytes.TrimSpace(lines[i])
+		if len(line) == 0 || line[0] != '{' {
+			continue
+		}
+		var result claudeResult
+		if err := json.Unmarshal(line, &result); err != nil {
+			continue
+		}
+		if result.Type == "result" {
+			return result, true
+		}
+	}
+	var result claudeResult
+	if err := json.Unmarshal(bytes.TrimSpace(output), &result); err == nil && result.Type == "result" {
+		return result, true
+	}
+	return claudeResult{}, false
+}
+
+func summarizeResult(text string) string {
+	text = strings.Join(strings.Fields(text), " ")
+	runes := []rune(text)
+	if len(runes) > resultSummaryLimit {
+		return string(runes[:resultSummaryLimit-1]) + "…"
+	}
+	return text
+}
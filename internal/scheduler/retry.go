@@ -0,0 +1,76 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	MaxRetries        = 5
+	DefaultRetryDelay = time.Minute
+	// A schedule with retries stops at this point even mid-attempt, so a
+	// wedged claude can't keep the job (and the Mac) awake forever.
+	MaxRetryWindow = 2 * time.Hour
+)
+
+// ParseRetryDelay accepts a Go duration like 30s or 2m; empty means the default.
+func ParseRetryDelay(value string) (time.Duration, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return DefaultRetryDelay, nil
+	}
+	delay, err := time.ParseDuration(value)
+	if err != nil || delay < time.Second {
+		return 0, fmt.Errorf("invalid retry delay: %s (use e.g. 30s or 2m)", value)
+	}
+	if delay > MaxRetryWindow {
+		return 0, fmt.Errorf("retry delay must be at most %s", MaxRetryWindow)
+	}
+	return delay, nil
+}
+
+// retryDelay doubles the configured delay after each failed attempt.
+func retryDelay(entry ScheduleEntry, attempt int) time.Duration {
+	delay, err := ParseRetryDelay(entry.RetryDelay)
+	if err != nil {
+		delay = DefaultRetryDelay
+	}
+	for i := 1; i < attempt && delay < MaxRetryWindow; i++ {
+		delay *= 2
+	}
+	return delay
+}
+
+func RetryLabel(entry ScheduleEntry) string {
+	if entry.Retries <= 0 {
+		return ""
+	}
+	delay, err := ParseRetryDelay(entry.RetryDelay)
+	if err != nil {
+		delay = DefaultRetryDelay
+	}
+	return fmt.Sprintf("up to %d after a failure, waiting %s then doubling", entry.Retries, delay)
+}
+
+// waitForRetry sleeps between attempts, keeping the Mac awake so the retry
+// isn't held until the next wake. It reports false if ctx ended first.
+func waitForRetry(ctx context.Context, delay time.Duration) bool {
+	if path, err := exec.LookPath("caffeinate"); err == nil {
+		caf := exec.CommandContext(ctx, path, "-i", "-s", "-t", strconv.Itoa(int(delay.Seconds())+1))
+		if caf.Start() == nil {
+			defer func() { _ = caf.Wait() }()
+		}
+	}
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
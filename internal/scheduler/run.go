@@ -1,10 +1,14 @@
 package scheduler
 
 import (
+	"bytes"
+	"context"
 	"errors"
 	"fmt"
+	"io"
 	"os"
 	"os/exec"
+	"os/signal"
 	"path/filepath"
 	"strconv"
 	"strings"
@@ -14,7 +18,27 @@ import (
 	"wakeclaude/internal/app"
 )
 
+const (
+	cancelGrace = 5 * time.Second
+	// Well under macOS's ARG_MAX (1 MiB shared with the environment) and
+	// Linux's 128 KiB per-argument limit.
+	maxPromptArgBytes = 64 * 1024
+)
+
+var errRunCancelled = errors.New("run cancelled")
+
 func RunSchedule(store *Store, id string) error {
+	return runSchedule(store, id, false)
+}
+
+// RunScheduleOnce runs a schedule right away for testing: pause and guards
+// are ignored, output is echoed to stdout, and the schedule itself is left
+// alone (no reschedule, one-time entries are kept).
+func RunScheduleOnce(store *Store, id string) error {
+	return runSchedule(store, id, true)
+}
+
+func runSchedule(store *Store, id string, manual bool) error {
 	schedules, err := store.LoadSchedules()
 	if err != nil {
 		return err
@@ -30,11 +54,128 @@ func RunSchedule(store *Store, id string) error {
 	if entry == nil {
 		return fmt.Errorf("schedule not found: %s", id)
 	}
+	if !manual && !firingDue(*entry, time.Now()) {
+		return nil
+	}
 	defer func() {
-		_ = store.PruneLogs(MaxRunLogs, MaxDaemonLogs, entry.UID, entry.GID)
+		runMax, daemonMax, perSchedule := LogRetention()
+		_ = store.PruneLogs(runMax, daemonMax, perSchedule, entry.UID, entry.GID)
 	}()
 
-	logEntry := LogEntry{
+	logEntry := newRunLog(*entry)
+
+	if err := store.Ensure(); err != nil {
+		logEntry.Error = err.Error()
+		_ = store.AppendLogWithOwnership(logEntry, entry.UID, entry.GID)
+		return err
+	}
+
+	if !manual && entry.Disabled {
+		logEntry.Status = "skipped"
+		logEntry.Error = "disabled"
+		_ = store.AppendLogWithOwnership(logEntry, entry.UID, entry.GID)
+		return nil
+	}
+
+	if !manual && logEntry.RanAt.Before(entry.StartAt) {
+		logEntry.Status = "skipped"
+		logEntry.Error = fmt.Sprintf("starts %s", StartDate(*entry, logEntry.RanAt))
+		_ = store.AppendLogWithOwnership(logEntry, entry.UID, entry.GID)
+		rescheduleNext(store, entry)
+		return nil
+	}
+
+	if !manual && logEntry.RanAt.Before(entry.PausedUntil) {
+		logEntry.Status = "paused"
+		_ = store.AppendLogWithOwnership(logEntry, entry.UID, entry.GID)
+		rescheduleNext(store, entry)
+		return nil
+	}
+
+	reason := ""
+	if !manual {
+		reason = batteryGuard(*entry)
+	}
+	if reason == "" && !manual {
+		reason = dirtyTreeGuard(*entry)
+	}
+	if reason == "" && !manual {
+		reason = networkGuard(*entry)
+	}
+	if reason != "" {
+		logEntry.Status = "skipped"
+		logEntry.Error = reason
+		_ = store.AppendLogWithOwnership(logEntry, entry.UID, entry.GID)
+		rescheduleNext(store, entry)
+		return nil
+	}
+
+	// ctrl+c on a foreground run, or launchd stopping the job, cancels it.
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	retries := min(entry.Retries, MaxRetries)
+	if manual {
+		retries = 0
+	}
+	if retries > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, MaxRetryWindow)
+		defer cancel()
+	}
+
+	for attempt := 1; ; attempt++ {
+		if retries > 0 {
+			logEntry.Attempt = attempt
+		}
+		if err := runAttempt(ctx, store, entry, &logEntry, manual); err != nil {
+			if !manual {
+				// A re-armed job has no next run until this sets one.
+				rescheduleNext(store, entry)
+			}
+			return err
+		}
+		_ = store.AppendLogWithOwnership(logEntry, entry.UID, entry.GID)
+		if logEntry.Status == "success" || logEntry.Status == "cancelled" || attempt > retries {
+			break
+		}
+		delay := retryDelay(*entry, attempt)
+		if deadline, ok := ctx.Deadline(); ok && time.Now().Add(delay).After(deadline) {
+			break
+		}
+		if !waitForRetry(ctx, delay) {
+			break
+		}
+		logEntry = newRunLog(*entry)
+	}
+	if ShouldNotify(*entry, logEntry) {
+		NotifyRun(*entry, logEntry)
+	}
+
+	if manual {
+		if logEntry.Status != "success" {
+			return fmt.Errorf("run %s: %s", logEntry.Status, logEntry.Error)
+		}
+		return nil
+	}
+
+	if entry.Schedule.Type == "once" {
+		if usesSystemd() {
+			_ = Backend.Remove(*entry)
+		} else {
+			RemoveLaunchdIfRoot(*entry)
+		}
+		_, _ = store.DeleteSchedule(entry.ID)
+		_ = os.Chown(store.Schedules, entry.UID, entry.GID)
+		return nil
+	}
+
+	rescheduleNext(store, entry)
+	return nil
+}
+
+func newRunLog(entry ScheduleEntry) LogEntry {
+	return LogEntry{
 		ID:            NewID(),
 		ScheduleID:    entry.ID,
 		RanAt:         time.Now(),
@@ -45,118 +186,310 @@ func RunSchedule(store *Store, id string) error {
 		NewSession:    entry.NewSession,
 		ProjectPath:   entry.ProjectPath,
 	}
+}
 
-	if err := store.Ensure(); err != nil {
-		logEntry.Error = err.Error()
-		_ = store.AppendLogWithOwnership(logEntry, entry.UID, entry.GID)
-		return err
-	}
-
-	outputPath := store.LogFilePath(logEntry)
+// runAttempt runs claude once and fills in logEntry. Errors are setup
+// failures, already written to the log; a failed run is only a status.
+func runAttempt(ctx context.Context, store *Store, entry *ScheduleEntry, logEntry *LogEntry, manual bool) error {
+	outputPath := store.LogFilePath(*logEntry)
 	if err := os.MkdirAll(filepath.Dir(outputPath), 0o755); err != nil {
 		logEntry.Error = err.Error()
-		_ = store.AppendLogWithOwnership(logEntry, entry.UID, entry.GID)
+		_ = store.AppendLogWithOwnership(*logEntry, entry.UID, entry.GID)
 		return err
 	}
 
 	outputFile, err := os.OpenFile(outputPath, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
 	if err != nil {
 		logEntry.Error = err.Error()
-		_ = store.AppendLogWithOwnership(logEntry, entry.UID, entry.GID)
+		_ = store.AppendLogWithOwnership(*logEntry, entry.UID, entry.GID)
 		return err
 	}
 	defer outputFile.Close()
 	_ = os.Chown(outputPath, entry.UID, entry.GID)
 
+	if _, ok := NormalizeModel(entry.Model); !ok {
+		fmt.Fprintf(outputFile, "wakeclaude: unrecognized model %q; passing it to claude as-is\n", entry.Model)
+	}
+	if logEntry.Attempt > 1 {
+		fmt.Fprintf(outputFile, "wakeclaude: attempt %d of %d\n", logEntry.Attempt, min(entry.Retries, MaxRetries)+1)
+	}
+
 	cmd, err := buildClaudeCommand(*entry)
 	if err != nil {
+		if errors.Is(err, app.ErrKeychainLocked) {
+			logEntry.Status = "locked"
+		}
 		logEntry.Error = err.Error()
-		_ = store.AppendLogWithOwnership(logEntry, entry.UID, entry.GID)
+		_ = store.AppendLogWithOwnership(*logEntry, entry.UID, entry.GID)
 		return err
 	}
 
-	cmd.Stdout = outputFile
-	cmd.Stderr = outputFile
+	var output io.Writer = outputFile
+	if cfg, err := app.LoadConfig(); err != nil || !cfg.RawOutput {
+		output = newANSIStripper(outputFile)
+	}
+	if manual {
+		output = io.MultiWriter(output, os.Stdout)
+	}
+	var stdout bytes.Buffer
+	cmd.Stdout = output
+	cmd.Stderr = output
+	if entry.OutputFormat == "json" {
+		cmd.Stdout = io.MultiWriter(output, &stdout)
+	}
+
+	// A "running" line lets the logs view follow the run; the final line,
+	// with the same ID, replaces it.
+	running := *logEntry
+	running.Status = "running"
+	running.OutputPath = outputPath
+	_ = store.AppendLogWithOwnership(running, entry.UID, entry.GID)
+
+	started := time.Now()
+	if entry.PreCommand != "" {
+		if err := runHook(ctx, *entry, "pre-command", entry.PreCommand, output, outputFile); err != nil {
+			logEntry.DurationMs = time.Since(started).Milliseconds()
+			logEntry.ExitCode = exitStatus(err)
+			logEntry.Error = fmt.Sprintf("pre-command failed: %v", err)
+			if errors.Is(err, errRunCancelled) && !errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				logEntry.Status = "cancelled"
+			}
+			logEntry.OutputPath = outputPath
+			return nil
+		}
+	}
 
 	exitCode := 0
-	if err := runWithCaffeinate(cmd, outputFile); err != nil {
+	err = runWithCaffeinate(ctx, cmd, outputFile)
+	// The post-command runs whatever claude's exit code; only a cancelled
+	// run skips it.
+	if entry.PostCommand != "" && ctx.Err() == nil {
+		_ = runHook(ctx, *entry, "post-command", entry.PostCommand, output, outputFile)
+	}
+	logEntry.DurationMs = time.Since(started).Milliseconds()
+	if err != nil {
 		exitCode = exitStatus(err)
 		logEntry.Error = err.Error()
+		if errors.Is(err, errRunCancelled) {
+			logEntry.Status = "cancelled"
+			if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				logEntry.Status = "error"
+				logEntry.Error = fmt.Sprintf("stopped at the %s retry limit", MaxRetryWindow)
+			}
+		}
 	} else {
 		logEntry.Status = "success"
 	}
 
+	if entry.OutputFormat == "json" {
+		if result, ok := parseClaudeResult(stdout.Bytes()); ok {
+			logEntry.ResultSummary = summarizeResult(result.Result)
+			if result.IsError && logEntry.Status == "success" {
+				logEntry.Status = "error"
+				logEntry.Error = "claude reported an error"
+				if result.Subtype != "" && result.Subtype != "success" {
+					logEntry.Error = fmt.Sprintf("claude reported an error (%s)", result.Subtype)
+				}
+			}
+			if result.SessionID != "" && (entry.NewSession || entry.ForkSession) && logEntry.Status == "success" {
+				logEntry.SessionID = result.SessionID
+			}
+		}
+	}
+
 	if logEntry.SessionID == "" && entry.NewSession && logEntry.Status == "success" {
 		if sessionID := findNewSessionID(*entry, logEntry.RanAt); sessionID != "" {
 			logEntry.SessionID = sessionID
 		}
 	}
+	if entry.ForkSession && !entry.NewSession && logEntry.Status == "success" && logEntry.SessionID == entry.SessionID {
+		logEntry.SessionID = findForkedSessionID(*entry, logEntry.RanAt)
+	}
+
+	if usage, ok := parseUsage(ReadTail(outputPath, usageScanLimit)); ok {
+		logEntry.InputTokens = usage.InputTokens + usage.CacheCreationTokens + usage.CacheReadTokens
+		logEntry.OutputTokens = usage.OutputTokens
+		logEntry.CostUSD = usage.CostUSD
+	}
 
 	logEntry.ExitCode = exitCode
 	logEntry.OutputPath = outputPath
-	_ = store.AppendLogWithOwnership(logEntry, entry.UID, entry.GID)
-	NotifyRun(*entry, logEntry)
+	return nil
+}
 
-	if entry.Schedule.Type == "once" {
-		RemoveLaunchdIfRoot(*entry)
-		_, _ = store.DeleteSchedule(entry.ID)
-		_ = os.Chown(store.Schedules, entry.UID, entry.GID)
+// The result object is the last thing claude prints, so only the end of a
+// long log is scanned.
+const usageScanLimit = 256 * 1024
+
+type runUsage struct {
+	claudeUsage
+	CostUSD float64
+}
+
+// parseUsage picks the token counts and cost out of the result object claude
+// prints with --output-format json (or stream-json); plain text output has
+// none, and ok is false.
+func parseUsage(output []byte) (runUsage, bool) {
+	result, ok := parseClaudeResult(output)
+	if !ok {
+		return runUsage{}, false
+	}
+	usage := runUsage{claudeUsage: result.Usage, CostUSD: result.CostUSD}
+	if usage.InputTokens == 0 && usage.OutputTokens == 0 && usage.CostUSD == 0 {
+		return runUsage{}, false
+	}
+	return usage, true
+}
+
+// ReadTail returns up to the last limit bytes of path, from a line start.
+func ReadTail(path string, limit int64) []byte {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil
+	}
+	defer file.Close()
+	info, err := file.Stat()
+	if err != nil {
 		return nil
 	}
+	offset := info.Size() - limit
+	if offset < 0 {
+		offset = 0
+	}
+	data := make([]byte, info.Size()-offset)
+	n, _ := file.ReadAt(data, offset)
+	data = data[:n]
+	// Drop the partial first line when starting mid-file.
+	if offset > 0 {
+		if i := bytes.IndexByte(data, '\n'); i >= 0 {
+			data = data[i+1:]
+		}
+	}
+	return data
+}
 
-	now := time.Now()
-	nextRun, err := NextRun(*entry, now)
-	if err == nil {
-		entry.NextRun = nextRun
-		entry.UpdatedAt = now
-		entry.WakeTime = FormatPMSet(nextRun)
-		_ = store.UpdateSchedule(*entry)
-		_ = os.Chown(store.Schedules, entry.UID, entry.GID)
-		if os.Geteuid() == 0 {
-			_ = ScheduleWake(*entry, entry.WakeTime)
+// firingDue filters launchd firings that don't line up with the schedule
+// (cron fallbacks, StartInterval drift, a re-armed schedule's job installed
+// by an older version that still fires on old times); those exit without a
+// log entry.
+func firingDue(entry ScheduleEntry, now time.Time) bool {
+	switch entry.Schedule.Type {
+	case "cron":
+		return cronDue(entry, now)
+	case "interval":
+		return intervalDue(entry, now)
+	default:
+		if rearmsEachRun(entry) {
+			return intervalDue(entry, now)
 		}
+		return true
 	}
+}
 
-	return nil
+func rescheduleNext(store *Store, entry *ScheduleEntry) {
+	now := time.Now()
+	nextRun, err := ResyncNextRun(*entry, now)
+	if err != nil {
+		return
+	}
+	entry.NextRun = nextRun
+	entry.UpdatedAt = now
+	entry.WakeTime = FormatPMSet(nextRun)
+	_ = store.UpdateSchedule(*entry)
+	_ = os.Chown(store.Schedules, entry.UID, entry.GID)
+	// Runs from systemd user timers aren't root; the wake goes through sudo -n.
+	if os.Geteuid() == 0 || usesSystemd() {
+		if rearmsEachRun(*entry) {
+			_ = Backend.Rearm(*entry)
+		}
+		_ = Backend.ScheduleWake(*entry, entry.WakeTime)
+	}
 }
 
 func buildClaudeCommand(entry ScheduleEntry) (*exec.Cmd, error) {
-	path, err := findInPath(entry.PathEnv, "claude")
+	path, err := ClaudePath(entry)
 	if err != nil {
-		return nil, fmt.Errorf("claude not found in PATH; install: %s", app.ClaudeInstallCmd)
+		return nil, err
 	}
 	token, err := loadOAuthToken(entry)
 	if err != nil {
 		return nil, err
 	}
+	return claudeCommand(entry, path, token, os.Geteuid() == 0 && entry.UID > 0), nil
+}
 
-	workDir := resolveWorkDir(entry)
-	if workDir == "" {
-		workDir = entry.HomeDir
+// DryRunCommand builds the command a run of entry would execute, with a
+// placeholder instead of the token; tokenPresent reports whether the
+// keychain has one. asRoot is what launchd uses for everything but login
+// schedules, so the output matches a scheduled run rather than this shell.
+func DryRunCommand(entry ScheduleEntry) (cmd *exec.Cmd, tokenPresent bool, err error) {
+	path, err := ClaudePath(entry)
+	if err != nil {
+		return nil, false, err
+	}
+	token, tokenErr := app.LoadOAuthToken()
+	tokenPresent = tokenErr == nil && strings.TrimSpace(token) != ""
+	placeholder := "(token present)"
+	if !tokenPresent {
+		placeholder = "(token missing)"
 	}
+	asRoot := entry.UID > 0 && entry.Schedule.Type != "login" && !usesSystemd()
+	return claudeCommand(entry, path, placeholder, asRoot), tokenPresent, nil
+}
 
+func claudeCommand(entry ScheduleEntry, path, token string, asRoot bool) *exec.Cmd {
 	args := []string{"-p"}
-	if entry.Model != "" && entry.Model != "auto" {
-		args = append(args, "--model", entry.Model)
+	if model, _ := NormalizeModel(entry.Model); model != "auto" {
+		args = append(args, "--model", model)
 	}
 	if entry.PermissionMode != "" && entry.PermissionMode != "default" {
 		args = append(args, "--permission-mode", entry.PermissionMode)
 	}
+	if entry.OutputFormat == "json" {
+		args = append(args, "--output-format", "json")
+	}
 	if !entry.NewSession && entry.SessionID != "" {
 		args = append(args, "--resume", entry.SessionID)
+		if entry.ForkSession {
+			args = append(args, "--fork-session")
+		}
 	}
-	args = append(args, entry.Prompt)
+	promptArgs, stdin := promptInput(entry.Prompt)
+	args = append(args, promptArgs...)
+
+	// The schedule's own variables go first so the token ones always win.
+	cmd := userCommand(entry, asRoot, append(envPairs(entry.Env),
+		"CLAUDE_CODE_OAUTH_TOKEN="+token,
+		"ANTHROPIC_API_KEY=",
+		"ANTHROPIC_AUTH_TOKEN=",
+	), path, args...)
+	cmd.Stdin = stdin
+	return cmd
+}
+
+// hookCommand runs a schedule's pre/post shell command the way claude runs:
+// as the schedule's user, in its work dir, with its HOME, PATH and Env. The
+// token is left out; hooks don't need it.
+func hookCommand(entry ScheduleEntry, command string) *exec.Cmd {
+	return userCommand(entry, os.Geteuid() == 0 && entry.UID > 0, envPairs(entry.Env), "/bin/sh", "-c", command)
+}
 
-	if os.Geteuid() == 0 && entry.UID > 0 {
-		cmd := exec.Command("/bin/launchctl", append([]string{
+// userCommand runs name as the schedule's user; asRoot means the caller is
+// the root daemon and has to switch users first. extraEnv is set on top of
+// HOME, USER, LOGNAME and PATH.
+func userCommand(entry ScheduleEntry, asRoot bool, extraEnv []string, name string, args ...string) *exec.Cmd {
+	home := RunHome(entry)
+	workDir, _ := ResolveWorkDir(entry)
+
+	if asRoot {
+		wrapper := []string{
 			"asuser", strconv.Itoa(entry.UID),
 			"/usr/bin/sudo", "-u", entry.User, "-H", "--",
 			"/usr/bin/env",
-			"CLAUDE_CODE_OAUTH_TOKEN=" + token,
-			"ANTHROPIC_API_KEY=",
-			"ANTHROPIC_AUTH_TOKEN=",
-			path,
-		}, args...)...)
+			"HOME=" + home,
+		}
+		wrapper = append(append(wrapper, extraEnv...), name)
+		cmd := exec.Command("/bin/launchctl", append(wrapper, args...)...)
 		cmd.Dir = workDir
 		cmd.Env = append(os.Environ(), []string{
 			"HOME=" + entry.HomeDir,
@@ -164,39 +497,78 @@ func buildClaudeCommand(entry ScheduleEntry) (*exec.Cmd, error) {
 			"LOGNAME=" + entry.User,
 			"PATH=" + entry.PathEnv,
 		}...)
-		return cmd, nil
+		return cmd
 	}
 
-	cmd := exec.Command(path, args...)
+	cmd := exec.Command(name, args...)
 	cmd.Dir = workDir
-
 	cmd.Env = append(os.Environ(), []string{
-		"HOME=" + entry.HomeDir,
+		"HOME=" + home,
 		"USER=" + entry.User,
 		"LOGNAME=" + entry.User,
 		"PATH=" + entry.PathEnv,
-		"CLAUDE_CODE_OAUTH_TOKEN=" + token,
-		"ANTHROPIC_API_KEY=",
-		"ANTHROPIC_AUTH_TOKEN=",
 	}...)
+	cmd.Env = append(cmd.Env, extraEnv...)
+	return cmd
+}
 
-	return cmd, nil
+// runHook runs a pre/post command with its output in the run's log.
+func runHook(ctx context.Context, entry ScheduleEntry, label, command string, output io.Writer, outputFile *os.File) error {
+	fmt.Fprintf(output, "wakeclaude: %s: %s\n", label, command)
+	cmd := hookCommand(entry, command)
+	cmd.Stdout = output
+	cmd.Stderr = output
+	err := runWithCaffeinate(ctx, cmd, outputFile)
+	if err != nil {
+		fmt.Fprintf(output, "wakeclaude: %s failed: %v\n", label, err)
+	}
+	return err
+}
+
+// promptInput passes long prompts on stdin, which `claude -p` reads when no
+// prompt argument is given, so they can't hit the argv size limit (ARG_MAX).
+func promptInput(prompt string) ([]string, io.Reader) {
+	if len(prompt) <= maxPromptArgBytes {
+		return []string{prompt}, nil
+	}
+	return nil, strings.NewReader(prompt)
 }
 
-func resolveWorkDir(entry ScheduleEntry) string {
+func RunHome(entry ScheduleEntry) string {
+	if entry.HomeOverride != "" {
+		return entry.HomeOverride
+	}
+	return entry.HomeDir
+}
+
+// ResolveWorkDir returns the directory claude runs in and where it came from:
+// the project path, the session's recorded cwd, or the run home as a fallback.
+func ResolveWorkDir(entry ScheduleEntry) (string, string) {
 	path := strings.TrimSpace(entry.ProjectPath)
-	if path != "" && isValidWorkDir(path) {
-		return path
+	if path != "" && IsValidWorkDir(path) {
+		return path, "project"
 	}
 	if entry.SessionPath != "" {
-		if cwd, err := app.ExtractCWD(entry.SessionPath); err == nil && isValidWorkDir(cwd) {
-			return cwd
+		if cwd, err := app.ExtractCWD(entry.SessionPath); err == nil && IsValidWorkDir(cwd) {
+			return cwd, "session cwd"
 		}
 	}
-	return ""
+	// A ~/.claude/projects dir stored as the project: use the cwd its
+	// sessions recorded, or its decoded name.
+	if cwd := app.ProjectCWD(path); path != "" && cwd != path && IsValidWorkDir(cwd) {
+		return cwd, "from claude project dir"
+	}
+	return RunHome(entry), WorkDirFallback
+}
+
+const WorkDirFallback = "home; project dir not found"
+
+func WorkDirLabel(entry ScheduleEntry) string {
+	dir, source := ResolveWorkDir(entry)
+	return fmt.Sprintf("%s (%s)", app.HumanizePath(dir), source)
 }
 
-func isValidWorkDir(path string) bool {
+func IsValidWorkDir(path string) bool {
 	if path == "" {
 		return false
 	}
@@ -237,6 +609,42 @@ func findNewSessionID(entry ScheduleEntry, since time.Time) string {
 	return ""
 }
 
+func findForkedSessionID(entry ScheduleEntry, since time.Time) string {
+	projectDir := findClaudeProjectDir(entry)
+	if projectDir == "" {
+		return ""
+	}
+	originalPath := entry.SessionPath
+	if originalPath == "" {
+		originalPath = filepath.Join(projectDir, entry.SessionID+".jsonl")
+	}
+	original, err := app.ExtractFirstUserText(originalPath)
+	if err != nil || original == "" {
+		return ""
+	}
+	sessions, err := app.CollectSessions(projectDir)
+	if err != nil {
+		return ""
+	}
+	cutoff := since.Add(-30 * time.Second)
+	for _, session := range sessions {
+		if session.ModTime.Before(cutoff) {
+			break
+		}
+		if session.ID == entry.SessionID {
+			continue
+		}
+		if !matchesPrompt(original, session.Path) {
+			continue
+		}
+		if os.Geteuid() == 0 && entry.UID > 0 {
+			_ = os.Chown(session.Path, entry.UID, entry.GID)
+		}
+		return session.ID
+	}
+	return ""
+}
+
 func matchesPrompt(prompt, sessionPath string) bool {
 	if strings.TrimSpace(prompt) == "" {
 		return false
@@ -279,8 +687,8 @@ func findClaudeProjectDir(entry ScheduleEntry) string {
 	}
 
 	root := ""
-	if entry.HomeDir != "" {
-		root = filepath.Join(entry.HomeDir, ".claude", "projects")
+	if home := RunHome(entry); home != "" {
+		root = filepath.Join(home, ".claude", "projects")
 	} else if resolved, err := app.DefaultProjectsRoot(); err == nil {
 		root = resolved
 	}
@@ -288,7 +696,7 @@ func findClaudeProjectDir(entry ScheduleEntry) string {
 		return ""
 	}
 
-	wanted, err := app.NormalizePath(entry.ProjectPath)
+	wanted, err := app.CanonicalPath(entry.ProjectPath)
 	if err != nil {
 		return ""
 	}
@@ -323,11 +731,42 @@ func findClaudeProjectDir(entry ScheduleEntry) string {
 }
 
 func samePath(path, wanted string) bool {
-	normalized, err := app.NormalizePath(path)
+	canonical, err := app.CanonicalPath(path)
 	if err != nil {
 		return false
 	}
-	return normalized == wanted
+	return canonical == wanted
+}
+
+// ClaudePath is the schedule's ClaudeBinary when set, else claude from its
+// recorded PATH.
+func ClaudePath(entry ScheduleEntry) (string, error) {
+	if entry.ClaudeBinary != "" {
+		if err := CheckExecutable(entry.ClaudeBinary); err != nil {
+			return "", fmt.Errorf("claude binary: %w", err)
+		}
+		return entry.ClaudeBinary, nil
+	}
+	path, err := findInPath(entry.PathEnv, "claude")
+	if err != nil {
+		return "", fmt.Errorf("claude not found in PATH; install: %s", app.ClaudeInstallCmd)
+	}
+	return path, nil
+}
+
+// CheckExecutable reports why path can't be run as a program, or nil.
+func CheckExecutable(path string) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	if info.IsDir() {
+		return fmt.Errorf("%s is a directory", path)
+	}
+	if info.Mode()&0o111 == 0 {
+		return fmt.Errorf("%s is not executable", path)
+	}
+	return nil
 }
 
 func findInPath(pathEnv, name string) (string, error) {
@@ -351,7 +790,9 @@ func findInPath(pathEnv, name string) (string, error) {
 	return "", exec.ErrNotFound
 }
 
-func runWithCaffeinate(cmd *exec.Cmd, outputFile *os.File) error {
+func runWithCaffeinate(ctx context.Context, cmd *exec.Cmd, outputFile *os.File) error {
+	// Own process group so a cancel reaches everything claude spawned.
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
 	if err := cmd.Start(); err != nil {
 		return err
 	}
@@ -365,7 +806,26 @@ func runWithCaffeinate(cmd *exec.Cmd, outputFile *os.File) error {
 		_ = caf.Start()
 	}
 
-	err := cmd.Wait()
+	done := make(chan error, 1)
+	go func() { done <- cmd.Wait() }()
+
+	var err error
+	select {
+	case err = <-done:
+	case <-ctx.Done():
+		pgid := cmd.Process.Pid
+		_ = syscall.Kill(-pgid, syscall.SIGTERM)
+		select {
+		case <-done:
+		case <-time.After(cancelGrace):
+			_ = syscall.Kill(-pgid, syscall.SIGKILL)
+			<-done
+		}
+		if caf != nil && caf.Process != nil {
+			_ = caf.Process.Kill()
+		}
+		err = errRunCancelled
+	}
 	if caf != nil {
 		_ = caf.Wait()
 	}
@@ -0,0 +1,52 @@
+package scheduler
+
+import (
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestClaudeCommandPromptInput(t *testing.T) {
+	tests := []struct {
+		name      string
+		size      int
+		wantStdin bool
+	}{
+		{"at the limit", maxPromptArgBytes, false},
+		{"over the limit", maxPromptArgBytes + 1, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			prompt := strings.Repeat("a", tt.size)
+			cmd := claudeCommand(ScheduleEntry{Prompt: prompt}, "/usr/local/bin/claude", "token", false)
+			hasArg := false
+			for _, arg := range cmd.Args {
+				if arg == prompt {
+					hasArg = true
+				}
+			}
+			if !tt.wantStdin {
+				if !hasArg {
+					t.Error("prompt not passed as an argument")
+				}
+				if cmd.Stdin != nil {
+					t.Error("stdin set for a prompt passed as an argument")
+				}
+				return
+			}
+			if hasArg {
+				t.Error("prompt passed as an argument")
+			}
+			if cmd.Stdin == nil {
+				t.Fatal("stdin not set")
+			}
+			data, err := io.ReadAll(cmd.Stdin)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(data) != prompt {
+				t.Errorf("stdin gave %d bytes, want the %d-byte prompt", len(data), len(prompt))
+			}
+		})
+	}
+}
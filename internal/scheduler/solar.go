@@ -0,0 +1,123 @@
+package scheduler
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+)
+
+const solarZenith = 90.833
+
+func nextSunEvent(event string, lat, lon float64, now time.Time, loc *time.Location) (time.Time, error) {
+	rising, err := sunEventRising(event)
+	if err != nil {
+		return time.Time{}, err
+	}
+	day := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, loc)
+	for i := 0; i <= 366; i++ {
+		candidate, ok := SunEventOn(day.AddDate(0, 0, i), lat, lon, rising)
+		if ok && candidate.After(now) {
+			return candidate, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("no %s at %.4f, %.4f within a year", event, lat, lon)
+}
+
+func sunEventRising(event string) (bool, error) {
+	switch strings.ToLower(strings.TrimSpace(event)) {
+	case "sunrise":
+		return true, nil
+	case "sunset":
+		return false, nil
+	default:
+		return false, fmt.Errorf("invalid sun event: %s", event)
+	}
+}
+
+func SunEventOn(date time.Time, lat, lon float64, rising bool) (time.Time, bool) {
+	loc := date.Location()
+	year, month, day := date.Date()
+	n := float64(time.Date(year, month, day, 0, 0, 0, 0, time.UTC).YearDay())
+
+	// NOAA sunrise equation; false means no event that day (polar day/night).
+	lngHour := lon / 15
+	t := n + (18-lngHour)/24
+	if rising {
+		t = n + (6-lngHour)/24
+	}
+
+	m := 0.9856*t - 3.289
+	l := normalizeDegrees(m + 1.916*sinDeg(m) + 0.020*sinDeg(2*m) + 282.634)
+
+	ra := normalizeDegrees(atanDeg(0.91764 * tanDeg(l)))
+	ra += math.Floor(l/90)*90 - math.Floor(ra/90)*90
+	ra /= 15
+
+	sinDec := 0.39782 * sinDeg(l)
+	cosDec := math.Cos(math.Asin(sinDec))
+	cosH := (cosDeg(solarZenith) - sinDec*sinDeg(lat)) / (cosDec * cosDeg(lat))
+	if cosH > 1 || cosH < -1 {
+		return time.Time{}, false
+	}
+
+	h := acosDeg(cosH)
+	if rising {
+		h = 360 - h
+	}
+	h /= 15
+
+	localMean := h + ra - 0.06571*t - 6.622
+	ut := math.Mod(localMean-lngHour, 24)
+	if ut < 0 {
+		ut += 24
+	}
+
+	event := time.Date(year, month, day, 0, 0, 0, 0, time.UTC).
+		Add(time.Duration(ut * float64(time.Hour))).
+		Round(time.Minute).
+		In(loc)
+	wanted := time.Date(year, month, day, 0, 0, 0, 0, loc)
+	eventDay := time.Date(event.Year(), event.Month(), event.Day(), 0, 0, 0, 0, loc)
+	if eventDay.Before(wanted) {
+		event = event.Add(24 * time.Hour)
+	} else if eventDay.After(wanted) {
+		event = event.Add(-24 * time.Hour)
+	}
+	return event, true
+}
+
+func ParseCoordinates(value string) (float64, float64, error) {
+	parts := strings.Split(value, ",")
+	if len(parts) != 2 {
+		return 0, 0, fmt.Errorf("enter latitude, longitude")
+	}
+	var lat, lon float64
+	if _, err := fmt.Sscanf(strings.TrimSpace(parts[0]), "%g", &lat); err != nil {
+		return 0, 0, fmt.Errorf("invalid latitude: %s", strings.TrimSpace(parts[0]))
+	}
+	if _, err := fmt.Sscanf(strings.TrimSpace(parts[1]), "%g", &lon); err != nil {
+		return 0, 0, fmt.Errorf("invalid longitude: %s", strings.TrimSpace(parts[1]))
+	}
+	if lat < -90 || lat > 90 {
+		return 0, 0, fmt.Errorf("latitude must be between -90 and 90")
+	}
+	if lon < -180 || lon > 180 {
+		return 0, 0, fmt.Errorf("longitude must be between -180 and 180")
+	}
+	return lat, lon, nil
+}
+
+func normalizeDegrees(value float64) float64 {
+	value = math.Mod(value, 360)
+	if value < 0 {
+		value += 360
+	}
+	return value
+}
+
+func sinDeg(deg float64) float64 { return math.Sin(deg * math.Pi / 180) }
+func cosDeg(deg float64) float64 { return math.Cos(deg * math.Pi / 180) }
+func tanDeg(deg float64) float64 { return math.Tan(deg * math.Pi / 180) }
+func atanDeg(x float64) float64  { return math.Atan(x) * 180 / math.Pi }
+func acosDeg(x float64) float64  { return math.Acos(x) * 180 / math.Pi }
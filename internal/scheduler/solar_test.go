@@ -0,0 +1,71 @@
+package scheduler
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSunEventOn(t *testing.T) {
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skip("no tz database:", err)
+	}
+	london, err := time.LoadLocation("Europe/London")
+	if err != nil {
+		t.Skip("no tz database:", err)
+	}
+
+	// Expected times are NOAA's solar calculator, to the minute.
+	tests := []struct {
+		name     string
+		date     time.Time
+		lat, lon float64
+		rising   bool
+		want     string
+	}{
+		{"new york sunrise, summer solstice", time.Date(2024, 6, 21, 0, 0, 0, 0, newYork), 40.7128, -74.0060, true, "05:25"},
+		{"new york sunset, summer solstice", time.Date(2024, 6, 21, 0, 0, 0, 0, newYork), 40.7128, -74.0060, false, "20:31"},
+		{"london sunrise, winter solstice", time.Date(2024, 12, 21, 0, 0, 0, 0, london), 51.5074, -0.1278, true, "08:04"},
+		{"london sunset, winter solstice", time.Date(2024, 12, 21, 0, 0, 0, 0, london), 51.5074, -0.1278, false, "15:53"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := SunEventOn(tt.date, tt.lat, tt.lon, tt.rising)
+			if !ok {
+				t.Fatalf("no event")
+			}
+			want, _ := time.ParseInLocation("2006-01-02 15:04", tt.date.Format("2006-01-02")+" "+tt.want, tt.date.Location())
+			if diff := got.Sub(want); diff < -2*time.Minute || diff > 2*time.Minute {
+				t.Errorf("got %s, want %s", got.Format("2006-01-02 15:04"), tt.want)
+			}
+		})
+	}
+}
+
+func TestSunEventOnPolar(t *testing.T) {
+	// Tromsø has no sunset around the summer solstice and no sunrise around
+	// the winter one.
+	const lat, lon = 69.6492, 18.9553
+	if _, ok := SunEventOn(time.Date(2024, 6, 21, 0, 0, 0, 0, time.UTC), lat, lon, false); ok {
+		t.Error("got a sunset during polar day")
+	}
+	if _, ok := SunEventOn(time.Date(2024, 12, 21, 0, 0, 0, 0, time.UTC), lat, lon, true); ok {
+		t.Error("got a sunrise during polar night")
+	}
+
+	// The next sunset skips the polar day instead of failing.
+	next, err := nextSunEvent("sunset", lat, lon, time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC), time.UTC)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if next.Month() != time.July {
+		t.Errorf("next sunset %s, want late July", next.Format("2006-01-02 15:04"))
+	}
+}
+
+func TestNextSunEventErrors(t *testing.T) {
+	now := time.Date(2024, 6, 21, 12, 0, 0, 0, time.UTC)
+	if _, err := nextSunEvent("noon", 40, -74, now, time.UTC); err == nil {
+		t.Error("accepted an unknown event")
+	}
+}
@@ -2,19 +2,36 @@ package scheduler
 
 import (
 	"bufio"
+	"bytes"
 	"encoding/json"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"sort"
 	"strings"
+	"syscall"
 	"time"
+
+	"wakeclaude/internal/app"
 )
 
 const (
 	scheduleVersion = 1
-	MaxRunLogs      = 50
-	MaxDaemonLogs   = 50
+
+	// The defaults when the config sets no maxRunLogs, maxDaemonLogs or
+	// minRunLogsPerSchedule.
+	MaxRunLogs            = 50
+	MaxDaemonLogs         = 50
+	MinRunLogsPerSchedule = 3
+
+	// How long a run may still be going: a "running" line older than this
+	// is shown as unknown, and only older output files without an index
+	// line are recovered. Such files still turn up from older versions, a
+	// failed append, or a "running" line lost when another run's prune
+	// rewrote the index at the same moment.
+	orphanLogGrace   = 6 * time.Hour
+	runLogTimeLayout = "20060102-150405"
 )
 
 type scheduleFile struct {
@@ -69,6 +86,57 @@ func (s *Store) SaveSchedules(entries []ScheduleEntry) error {
 	return os.Rename(tmp, s.Schedules)
 }
 
+// ExportSchedules writes the schedules file as a backup that ParseSchedules
+// reads back.
+func (s *Store) ExportSchedules(w io.Writer) error {
+	entries, err := s.LoadSchedules()
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(scheduleFile{Version: scheduleVersion, Schedules: entries}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encode schedules: %w", err)
+	}
+	if _, err := w.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("write schedules: %w", err)
+	}
+	return nil
+}
+
+// ParseSchedules reads schedules from an ExportSchedules backup, or from the
+// json array or ndjson that `wakeclaude export` writes.
+func ParseSchedules(data []byte) ([]ScheduleEntry, error) {
+	data = bytes.TrimSpace(data)
+	switch {
+	case len(data) == 0:
+		return nil, nil
+	case data[0] == '[':
+		var entries []ScheduleEntry
+		if err := json.Unmarshal(data, &entries); err != nil {
+			return nil, fmt.Errorf("parse schedules: %w", err)
+		}
+		return entries, nil
+	}
+
+	var file scheduleFile
+	if err := json.Unmarshal(data, &file); err == nil && file.Schedules != nil {
+		return file.Schedules, nil
+	}
+	var entries []ScheduleEntry
+	for i, line := range bytes.Split(data, []byte("\n")) {
+		line = bytes.TrimSpace(line)
+		if len(line) == 0 {
+			continue
+		}
+		var entry ScheduleEntry
+		if err := json.Unmarshal(line, &entry); err != nil {
+			return nil, fmt.Errorf("parse schedules: line %d: %w", i+1, err)
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
 func (s *Store) AddSchedule(entry ScheduleEntry) (ScheduleEntry, error) {
 	entries, err := s.LoadSchedules()
 	if err != nil {
@@ -127,6 +195,27 @@ func (s *Store) DeleteSchedule(id string) (ScheduleEntry, error) {
 	return deleted, nil
 }
 
+// LogRetention is how many run and daemon logs to keep, and how many runs of
+// each schedule to keep past runMax, from the config or the defaults. A max
+// of 0 keeps everything; a perSchedule of 0 keeps no extra runs.
+func LogRetention() (runMax, daemonMax, perSchedule int) {
+	runMax, daemonMax, perSchedule = MaxRunLogs, MaxDaemonLogs, MinRunLogsPerSchedule
+	cfg, err := app.LoadConfig()
+	if err != nil {
+		return runMax, daemonMax, perSchedule
+	}
+	if cfg.MaxRunLogs != nil && *cfg.MaxRunLogs >= 0 {
+		runMax = *cfg.MaxRunLogs
+	}
+	if cfg.MaxDaemonLogs != nil && *cfg.MaxDaemonLogs >= 0 {
+		daemonMax = *cfg.MaxDaemonLogs
+	}
+	if cfg.MinRunLogsPerSchedule != nil && *cfg.MinRunLogsPerSchedule >= 0 {
+		perSchedule = *cfg.MinRunLogsPerSchedule
+	}
+	return runMax, daemonMax, perSchedule
+}
+
 func (s *Store) LoadLogs(limit int) ([]LogEntry, error) {
 	if err := s.Ensure(); err != nil {
 		return nil, err
@@ -142,6 +231,8 @@ func (s *Store) LoadLogs(limit int) ([]LogEntry, error) {
 	defer file.Close()
 
 	var entries []LogEntry
+	// A run's final line replaces its earlier "running" one.
+	byID := make(map[string]int)
 	scanner := bufio.NewScanner(file)
 	for scanner.Scan() {
 		line := strings.TrimSpace(scanner.Text())
@@ -149,13 +240,26 @@ func (s *Store) LoadLogs(limit int) ([]LogEntry, error) {
 			continue
 		}
 		var entry LogEntry
-		if err := json.Unmarshal([]byte(line), &entry); err == nil {
-			entries = append(entries, entry)
+		if err := json.Unmarshal([]byte(line), &entry); err != nil {
+			continue
+		}
+		if i, ok := byID[entry.ID]; ok && entry.ID != "" {
+			entries[i] = entry
+			continue
 		}
+		byID[entry.ID] = len(entries)
+		entries = append(entries, entry)
 	}
 	if err := scanner.Err(); err != nil {
 		return nil, fmt.Errorf("read logs: %w", err)
 	}
+	now := time.Now()
+	for i := range entries {
+		// The run was killed before it could log how it ended.
+		if entries[i].Status == "running" && now.Sub(entries[i].RanAt) > orphanLogGrace {
+			entries[i].Status = "unknown"
+		}
+	}
 
 	sort.Slice(entries, func(i, j int) bool {
 		return entries[i].RanAt.After(entries[j].RanAt)
@@ -200,11 +304,11 @@ func (s *Store) AppendLogWithOwnership(entry LogEntry, uid, gid int) error {
 }
 
 func (s *Store) LogFilePath(entry LogEntry) string {
-	name := fmt.Sprintf("run-%s-%s.log", entry.ScheduleID, entry.RanAt.Format("20060102-150405"))
+	name := fmt.Sprintf("run-%s-%s.log", entry.ScheduleID, entry.RanAt.Format(runLogTimeLayout))
 	return filepath.Join(s.LogsDir, name)
 }
 
-func (s *Store) PruneLogs(runMax, daemonMax int, uid, gid int) error {
+func (s *Store) PruneLogs(runMax, daemonMax, perSchedule int, uid, gid int) error {
 	if runMax <= 0 && daemonMax <= 0 {
 		return nil
 	}
@@ -212,13 +316,22 @@ func (s *Store) PruneLogs(runMax, daemonMax int, uid, gid int) error {
 		return err
 	}
 
+	if _, err := s.RecoverOrphanLogs(uid, gid); err != nil {
+		return err
+	}
+
 	entries, err := s.LoadLogs(0)
 	if err != nil {
 		return err
 	}
-	if runMax > 0 && len(entries) > runMax {
-		entries = entries[:runMax]
+	var live map[string]bool
+	if schedules, err := s.LoadSchedules(); err == nil {
+		live = make(map[string]bool, len(schedules))
+		for _, schedule := range schedules {
+			live[schedule.ID] = true
+		}
 	}
+	entries = retainLogs(entries, runMax, perSchedule, live)
 
 	keepPaths := make(map[string]struct{}, len(entries))
 	for _, entry := range entries {
@@ -247,6 +360,169 @@ func (s *Store) PruneLogs(runMax, daemonMax int, uid, gid int) error {
 	return nil
 }
 
+func (s *Store) RecoverOrphanLogs(uid, gid int) (int, error) {
+	files, err := s.listLogFiles("run-", ".log")
+	if err != nil || len(files) == 0 {
+		return 0, err
+	}
+	entries, err := s.LoadLogs(0)
+	if err != nil {
+		return 0, err
+	}
+	indexed := make(map[string]struct{}, len(entries))
+	for _, entry := range entries {
+		path := entry.OutputPath
+		if path == "" {
+			path = s.LogFilePath(entry)
+		}
+		indexed[filepath.Clean(path)] = struct{}{}
+	}
+
+	schedules, _ := s.LoadSchedules()
+	now := time.Now()
+	recovered := 0
+	for _, file := range files {
+		if _, ok := indexed[filepath.Clean(file.path)]; ok {
+			continue
+		}
+		scheduleID, ranAt, ok := parseRunLogName(filepath.Base(file.path))
+		if !ok || now.Sub(ranAt) < orphanLogGrace {
+			continue
+		}
+		entry := LogEntry{
+			ID:         NewID(),
+			ScheduleID: scheduleID,
+			RanAt:      ranAt,
+			Status:     "unknown",
+			Error:      "run ended before it was logged",
+			OutputPath: file.path,
+		}
+		for _, schedule := range schedules {
+			if schedule.ID == scheduleID {
+				entry.PromptPreview = Preview(schedule.Prompt, 120)
+				entry.Model = schedule.Model
+				entry.ProjectPath = schedule.ProjectPath
+				break
+			}
+		}
+		if err := s.AppendLogWithOwnership(entry, uid, gid); err != nil {
+			return recovered, err
+		}
+		recovered++
+	}
+	return recovered, nil
+}
+
+// ClearLogs drops runs from the index along with their output files: all of
+// them for "all", or those that neither succeeded nor were skipped for
+// "failed". Runs still going are always kept. It returns how many were
+// removed.
+func (s *Store) ClearLogs(filter string) (int, error) {
+	if filter != "all" && filter != "failed" {
+		return 0, fmt.Errorf("unknown log filter: %q", filter)
+	}
+	if err := s.Ensure(); err != nil {
+		return 0, err
+	}
+	uid, gid := fileOwner(s.Logs)
+	// Orphaned output files become entries first, so they are cleared too.
+	if _, err := s.RecoverOrphanLogs(uid, gid); err != nil {
+		return 0, err
+	}
+	entries, err := s.LoadLogs(0)
+	if err != nil {
+		return 0, err
+	}
+
+	kept := make([]LogEntry, 0, len(entries))
+	removePaths := make(map[string]struct{})
+	for _, entry := range entries {
+		path := entry.OutputPath
+		if path == "" {
+			path = s.LogFilePath(entry)
+		}
+		if entry.Status == "running" || (filter == "failed" && !failedLog(entry)) {
+			kept = append(kept, entry)
+			continue
+		}
+		removePaths[filepath.Clean(path)] = struct{}{}
+	}
+	removed := len(entries) - len(kept)
+	if removed == 0 {
+		return 0, nil
+	}
+
+	if err := s.writeLogIndex(kept, uid, gid); err != nil {
+		return 0, err
+	}
+	files, err := s.listLogFiles("run-", ".log")
+	if err != nil {
+		return removed, err
+	}
+	for _, file := range files {
+		if _, ok := removePaths[filepath.Clean(file.path)]; ok {
+			_ = os.Remove(file.path)
+		}
+	}
+	return removed, nil
+}
+
+func failedLog(entry LogEntry) bool {
+	switch entry.Status {
+	case "success", "skipped", "paused", "running":
+		return false
+	}
+	return true
+}
+
+// fileOwner is -1, -1 (leave as is) when path can't be read.
+func fileOwner(path string) (int, int) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return -1, -1
+	}
+	stat, ok := info.Sys().(*syscall.Stat_t)
+	if !ok {
+		return -1, -1
+	}
+	return int(stat.Uid), int(stat.Gid)
+}
+
+func parseRunLogName(name string) (string, time.Time, bool) {
+	name = strings.TrimSuffix(strings.TrimPrefix(name, "run-"), ".log")
+	if len(name) < len(runLogTimeLayout)+2 {
+		return "", time.Time{}, false
+	}
+	split := len(name) - len(runLogTimeLayout)
+	if name[split-1] != '-' {
+		return "", time.Time{}, false
+	}
+	ranAt, err := time.ParseInLocation(runLogTimeLayout, name[split:], time.Local)
+	if err != nil {
+		return "", time.Time{}, false
+	}
+	return name[:split-1], ranAt, true
+}
+
+// retainLogs keeps the newest runMax entries, plus up to perSchedule of each
+// schedule in live so a quiet schedule keeps some history. Deleted schedules
+// get no floor; a nil live (schedules unreadable) gives every one a floor.
+func retainLogs(entries []LogEntry, runMax, perSchedule int, live map[string]bool) []LogEntry {
+	if runMax <= 0 || len(entries) <= runMax {
+		return entries
+	}
+	counts := make(map[string]int)
+	kept := make([]LogEntry, 0, runMax)
+	for i, entry := range entries {
+		floor := live == nil || live[entry.ScheduleID]
+		if i < runMax || (floor && counts[entry.ScheduleID] < perSchedule) {
+			kept = append(kept, entry)
+			counts[entry.ScheduleID]++
+		}
+	}
+	return kept
+}
+
 func (s *Store) writeLogIndex(entries []LogEntry, uid, gid int) error {
 	if len(entries) == 0 {
 		if _, err := os.Stat(s.Logs); err != nil {
@@ -0,0 +1,42 @@
+package scheduler
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRetainLogs(t *testing.T) {
+	// Newest first, as LoadLogs returns them; the first letter is the schedule.
+	ids := []string{"a1", "a2", "a3", "a4", "b1", "b2", "c1", "c2", "c3", "c4"}
+	live := map[string]bool{"a": true, "b": true, "c": true}
+	tests := []struct {
+		name        string
+		runMax      int
+		perSchedule int
+		live        map[string]bool
+		want        string
+	}{
+		{"under the cap", 20, 3, live, "a1 a2 a3 a4 b1 b2 c1 c2 c3 c4"},
+		{"no cap", 0, 3, live, "a1 a2 a3 a4 b1 b2 c1 c2 c3 c4"},
+		{"global cap only", 3, 0, live, "a1 a2 a3"},
+		{"per-schedule floor", 3, 2, live, "a1 a2 a3 b1 b2 c1 c2"},
+		{"deleted schedule gets no floor", 3, 2, map[string]bool{"a": true, "b": true}, "a1 a2 a3 b1 b2"},
+		{"nil live keeps every floor", 3, 2, nil, "a1 a2 a3 b1 b2 c1 c2"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entries := make([]LogEntry, 0, len(ids))
+			for _, id := range ids {
+				entries = append(entries, LogEntry{ID: id, ScheduleID: id[:1]})
+			}
+			kept := retainLogs(entries, tt.runMax, tt.perSchedule, tt.live)
+			got := make([]string, 0, len(kept))
+			for _, entry := range kept {
+				got = append(got, entry.ID)
+			}
+			if strings.Join(got, " ") != tt.want {
+				t.Errorf("got %s, want %s", strings.Join(got, " "), tt.want)
+			}
+		})
+	}
+}
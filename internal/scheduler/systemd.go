@@ -0,0 +1,263 @@
+package scheduler
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"wakeclaude/internal/app"
+)
+
+const rtcWakeAlarmPath = "/sys/class/rtc/rtc0/wakealarm"
+
+// systemdScheduler runs each schedule from a systemd user timer, so nothing
+// but the RTC wake needs root.
+type systemdScheduler struct{}
+
+func systemdUnitDir(entry ScheduleEntry) string {
+	return filepath.Join(entry.HomeDir, ".config", "systemd", "user")
+}
+
+func systemdUnitName(id, kind string) string {
+	return fmt.Sprintf("wakeclaude-%s.%s", id, kind)
+}
+
+func (systemdScheduler) Ensure(entry ScheduleEntry) error {
+	var intervals []map[string]int
+	if entry.Schedule.Type != "login" && entry.Schedule.Type != "interval" {
+		var err error
+		if intervals, err = calendarIntervals(entry); err != nil {
+			return err
+		}
+	}
+
+	dir := systemdUnitDir(entry)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("create systemd user dir: %w", err)
+	}
+	service := systemdUnitName(entry.ID, "service")
+	if err := os.WriteFile(filepath.Join(dir, service), buildServiceUnit(entry), 0o644); err != nil {
+		return fmt.Errorf("write systemd service: %w", err)
+	}
+	if entry.Schedule.Type == "login" {
+		// Like a launch agent: enabled to start with the user's session,
+		// not started now.
+		if err := systemctlUser("daemon-reload"); err != nil {
+			return err
+		}
+		return systemctlUser("enable", service)
+	}
+
+	timer := systemdUnitName(entry.ID, "timer")
+	if err := os.WriteFile(filepath.Join(dir, timer), buildTimerUnit(entry, intervals), 0o644); err != nil {
+		return fmt.Errorf("write systemd timer: %w", err)
+	}
+	if err := systemctlUser("daemon-reload"); err != nil {
+		return err
+	}
+	if err := systemctlUser("enable", timer); err != nil {
+		return err
+	}
+	return systemctlUser("restart", timer)
+}
+
+// Remove stops the timer but not the service, which may be the run calling
+// this for a one-time schedule.
+func (systemdScheduler) Remove(entry ScheduleEntry) error {
+	dir := systemdUnitDir(entry)
+	service := systemdUnitName(entry.ID, "service")
+	timer := systemdUnitName(entry.ID, "timer")
+	if entry.Schedule.Type == "login" {
+		_ = systemctlUser("disable", service)
+	} else {
+		_ = systemctlUser("disable", "--now", timer)
+	}
+	_ = os.Remove(filepath.Join(dir, timer))
+	_ = os.Remove(filepath.Join(dir, service))
+	_ = systemctlUser("daemon-reload")
+	return nil
+}
+
+// Rearm can simply rewrite the timer: restarting it leaves the running
+// service alone.
+func (s systemdScheduler) Rearm(entry ScheduleEntry) error { return s.Ensure(entry) }
+
+// The RTC holds a single alarm, so a wake is only set when it comes before
+// the pending one. Without rtcwake the timer still runs whenever the machine
+// is awake.
+func (systemdScheduler) ScheduleWake(entry ScheduleEntry, when string) error {
+	if when == "" || entry.NextRun.IsZero() {
+		return nil
+	}
+	if _, err := exec.LookPath("rtcwake"); err != nil {
+		return nil
+	}
+	at := entry.NextRun.Unix()
+	if pending := readWakeAlarm(); pending > time.Now().Unix() && pending <= at {
+		return nil
+	}
+	if err := runWakeCommand("rtcwake", "-m", "no", "-t", strconv.FormatInt(at, 10)); err != nil {
+		return fmt.Errorf("rtcwake: %w", err)
+	}
+	return nil
+}
+
+// CancelWake clears the alarm only while it is still this entry's.
+func (systemdScheduler) CancelWake(entry ScheduleEntry) error {
+	if entry.WakeTime == "" || entry.NextRun.IsZero() {
+		return nil
+	}
+	if _, err := exec.LookPath("rtcwake"); err != nil {
+		return nil
+	}
+	if readWakeAlarm() != entry.NextRun.Unix() {
+		return nil
+	}
+	return runWakeCommand("rtcwake", "-m", "disable")
+}
+
+func systemdCommands(entry ScheduleEntry, install bool) []string {
+	if entry.WakeTime == "" || (install && entry.Disabled) {
+		return nil
+	}
+	if _, err := exec.LookPath("rtcwake"); err != nil {
+		return nil
+	}
+	if !install {
+		return []string{"rtcwake -m disable"}
+	}
+	return []string{fmt.Sprintf("rtcwake -m no -t %d", entry.NextRun.Unix())}
+}
+
+func readWakeAlarm() int64 {
+	data, err := os.ReadFile(rtcWakeAlarmPath)
+	if err != nil {
+		return 0
+	}
+	value, err := strconv.ParseInt(strings.TrimSpace(string(data)), 10, 64)
+	if err != nil {
+		return 0
+	}
+	return value
+}
+
+// runWakeCommand is runSudo, except that a scheduled run (no terminal to ask
+// for a password on) only succeeds when sudo needs none.
+func runWakeCommand(args ...string) error {
+	if os.Geteuid() == 0 || stdinIsTerminal() {
+		return runSudo(args...)
+	}
+	return exec.Command("sudo", append([]string{"-n"}, args...)...).Run()
+}
+
+func stdinIsTerminal() bool {
+	info, err := os.Stdin.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+func systemctlUser(args ...string) error {
+	cmd := exec.Command("systemctl", append([]string{"--user"}, args...)...)
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		if msg := strings.TrimSpace(string(output)); msg != "" {
+			return fmt.Errorf("systemctl --user %s: %s", strings.Join(args, " "), msg)
+		}
+		return fmt.Errorf("systemctl --user %s: %w", strings.Join(args, " "), err)
+	}
+	return nil
+}
+
+func buildServiceUnit(entry ScheduleEntry) []byte {
+	env := map[string]string{
+		"PATH":    entry.PathEnv,
+		"HOME":    entry.HomeDir,
+		"USER":    entry.User,
+		"LOGNAME": entry.User,
+	}
+	if dir := app.DataDirOverride(); dir != "" {
+		env[app.DataDirEnv] = dir
+	}
+	logsDir := filepath.Join(app.SupportDirFor(entry.HomeDir), "logs")
+
+	var b strings.Builder
+	b.WriteString("[Unit]\n")
+	fmt.Fprintf(&b, "Description=wakeclaude schedule %s\n\n", entry.ID)
+	b.WriteString("[Service]\n")
+	b.WriteString("Type=oneshot\n")
+	fmt.Fprintf(&b, "ExecStart=%s\n", systemdQuoteArgs(entry.BinaryPath, "--run", entry.ID))
+	for _, key := range sortedKeys(env) {
+		fmt.Fprintf(&b, "Environment=%s\n", systemdQuote(key+"="+env[key]))
+	}
+	fmt.Fprintf(&b, "StandardOutput=append:%s\n", filepath.Join(logsDir, fmt.Sprintf("daemon-%s.out.log", entry.ID)))
+	fmt.Fprintf(&b, "StandardError=append:%s\n", filepath.Join(logsDir, fmt.Sprintf("daemon-%s.err.log", entry.ID)))
+	if entry.LowPriority {
+		fmt.Fprintf(&b, "Nice=%d\n", lowPriorityNice)
+		b.WriteString("IOSchedulingClass=idle\n")
+	}
+	if entry.Schedule.Type == "login" {
+		b.WriteString("\n[Install]\nWantedBy=default.target\n")
+	}
+	return []byte(b.String())
+}
+
+func buildTimerUnit(entry ScheduleEntry, intervals []map[string]int) []byte {
+	var b strings.Builder
+	b.WriteString("[Unit]\n")
+	fmt.Fprintf(&b, "Description=wakeclaude schedule %s\n\n", entry.ID)
+	b.WriteString("[Timer]\n")
+	if seconds := intervalSeconds(entry); seconds > 0 {
+		fmt.Fprintf(&b, "OnActiveSec=%ds\n", seconds)
+		fmt.Fprintf(&b, "OnUnitActiveSec=%ds\n", seconds)
+	}
+	for _, interval := range intervals {
+		fmt.Fprintf(&b, "OnCalendar=%s\n", onCalendar(interval, entry.Timezone))
+	}
+	// Like launchd, runs missed while asleep or off fire on the next boot.
+	b.WriteString("Persistent=true\n")
+	b.WriteString("AccuracySec=1s\n\n")
+	b.WriteString("[Install]\nWantedBy=timers.target\n")
+	return []byte(b.String())
+}
+
+// onCalendar turns a launchd calendar interval into a systemd calendar
+// event; a missing key matches every value, as it does for launchd.
+func onCalendar(interval map[string]int, timezone string) string {
+	field := func(key, format string) string {
+		if value, ok := interval[key]; ok {
+			return fmt.Sprintf(format, value)
+		}
+		return "*"
+	}
+	spec := fmt.Sprintf("%s-%s-%s %s:%s:00",
+		field("Year", "%04d"), field("Month", "%02d"), field("Day", "%02d"),
+		field("Hour", "%02d"), field("Minute", "%02d"))
+	if weekday, ok := interval["Weekday"]; ok && weekday >= 0 && weekday <= 6 {
+		name := cronWeekdayNames[weekday]
+		spec = strings.ToUpper(name[:1]) + name[1:] + " " + spec
+	}
+	if timezone != "" {
+		spec += " " + timezone
+	}
+	return spec
+}
+
+func systemdQuoteArgs(args ...string) string {
+	quoted := make([]string, 0, len(args))
+	for _, arg := range args {
+		quoted = append(quoted, systemdQuote(arg))
+	}
+	return strings.Join(quoted, " ")
+}
+
+// systemdQuote double-quotes value for a unit file; % starts a specifier
+// there, so it is doubled.
+func systemdQuote(value string) string {
+	value = strings.ReplaceAll(value, `\`, `\\`)
+	value = strings.ReplaceAll(value, `"`, `\"`)
+	value = strings.ReplaceAll(value, "%", "%%")
+	return `"` + value + `"`
+}
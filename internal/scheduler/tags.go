@@ -0,0 +1,34 @@
+package scheduler
+
+import "strings"
+
+func ParseTags(value string) []string {
+	fields := strings.FieldsFunc(value, func(r rune) bool {
+		return r == ',' || r == ' ' || r == '\t'
+	})
+	seen := make(map[string]struct{})
+	tags := make([]string, 0, len(fields))
+	for _, field := range fields {
+		tag := strings.ToLower(strings.TrimLeft(field, "#"))
+		if tag == "" {
+			continue
+		}
+		if _, ok := seen[tag]; ok {
+			continue
+		}
+		seen[tag] = struct{}{}
+		tags = append(tags, tag)
+	}
+	if len(tags) == 0 {
+		return nil
+	}
+	return tags
+}
+
+func FormatTags(tags []string) string {
+	labels := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		labels = append(labels, "#"+tag)
+	}
+	return strings.Join(labels, " ")
+}
@@ -2,16 +2,17 @@ package scheduler
 
 import (
 	"fmt"
+	"sort"
 	"strings"
 	"time"
 )
 
 func NextRun(entry ScheduleEntry, now time.Time) (time.Time, error) {
-	loc := time.Local
-	if entry.Timezone != "" {
-		if location, err := time.LoadLocation(entry.Timezone); err == nil {
-			loc = location
-		}
+	loc := entryLocation(entry)
+	// A recurring schedule with a start date begins at its first occurrence
+	// on or after it.
+	if entry.Schedule.Type != "once" && now.Before(entry.StartAt) {
+		now = entry.StartAt.Add(-time.Nanosecond)
 	}
 
 	switch entry.Schedule.Type {
@@ -25,14 +26,85 @@ func NextRun(entry ScheduleEntry, now time.Time) (time.Time, error) {
 		}
 		return parsed, nil
 	case "daily":
-		return nextDaily(entry.Schedule.Time, now.In(loc), loc), nil
+		return nextDaily(ScheduleTimes(entry.Schedule), now.In(loc), loc), nil
 	case "weekly":
-		return nextWeekly(entry.Schedule.Weekday, entry.Schedule.Time, now.In(loc), loc)
+		return nextWeekly(entry.Schedule.Weekday, ScheduleTimes(entry.Schedule), now.In(loc), loc)
+	case "monthly":
+		return nextMonthly(entry.Schedule.Day, entry.Schedule.Time, now.In(loc), loc)
+	case "cron":
+		return nextCron(entry.Schedule.Cron, now.In(loc), loc)
+	case "interval":
+		return nextInterval(entry.Schedule.IntervalMinutes, entry.CreatedAt, now)
+	case "sun":
+		return nextSunEvent(entry.Schedule.Event, entry.Schedule.Latitude, entry.Schedule.Longitude, now.In(loc), loc)
+	case "login":
+		// Runs whenever the user logs in; there is no time to compute.
+		return time.Time{}, nil
 	default:
 		return time.Time{}, fmt.Errorf("unknown schedule type: %s", entry.Schedule.Type)
 	}
 }
 
+func NextRuns(entry ScheduleEntry, now time.Time, count int) []time.Time {
+	runs := make([]time.Time, 0, count)
+	cursor := now
+	for len(runs) < count {
+		next, err := NextRun(entry, cursor)
+		if err != nil || !next.After(cursor) {
+			break
+		}
+		runs = append(runs, next)
+		if entry.Schedule.Type == "once" {
+			break
+		}
+		cursor = next
+	}
+	return runs
+}
+
+func entryLocation(entry ScheduleEntry) *time.Location {
+	if entry.Timezone != "" {
+		if location, err := time.LoadLocation(entry.Timezone); err == nil {
+			return location
+		}
+	}
+	return time.Local
+}
+
+// ZoneSuffix is " EST"-style: the abbreviation of entry's time zone at t,
+// or "" when that zone matches the local one.
+func ZoneSuffix(entry ScheduleEntry, t time.Time) string {
+	name, offset := t.In(entryLocation(entry)).Zone()
+	localName, localOffset := t.In(time.Local).Zone()
+	if name == localName && offset == localOffset {
+		return ""
+	}
+	return " " + name
+}
+
+// FormatInZone formats t in entry's time zone, labeled with ZoneSuffix.
+func FormatInZone(entry ScheduleEntry, t time.Time, layout string) string {
+	return t.In(entryLocation(entry)).Format(layout) + ZoneSuffix(entry, t)
+}
+
+// ParseStartDate turns a YYYY-MM-DD start date into midnight of that day in
+// timezone (the local zone when empty or unknown).
+func ParseStartDate(value, timezone string) (time.Time, error) {
+	start, err := time.ParseInLocation("2006-01-02", strings.TrimSpace(value), entryLocation(ScheduleEntry{Timezone: timezone}))
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid start date: %s (use YYYY-MM-DD)", value)
+	}
+	return start, nil
+}
+
+// StartDate is the entry's start date as YYYY-MM-DD, or "" once it has passed.
+func StartDate(entry ScheduleEntry, now time.Time) string {
+	if !now.Before(entry.StartAt) {
+		return ""
+	}
+	return entry.StartAt.In(entryLocation(entry)).Format("2006-01-02")
+}
+
 func parseDateTime(date, clock string, loc *time.Location) (time.Time, error) {
 	if date == "" || clock == "" {
 		return time.Time{}, fmt.Errorf("date/time required")
@@ -44,27 +116,156 @@ func parseDateTime(date, clock string, loc *time.Location) (time.Time, error) {
 	return parsed, nil
 }
 
-func nextDaily(clock string, now time.Time, loc *time.Location) time.Time {
-	hour, min := parseClock(clock)
-	candidate := time.Date(now.Year(), now.Month(), now.Day(), hour, min, 0, 0, loc)
-	if !candidate.After(now) {
-		candidate = candidate.Add(24 * time.Hour)
+func nextDaily(clocks []string, now time.Time, loc *time.Location) time.Time {
+	if len(clocks) == 0 {
+		clocks = []string{""}
 	}
-	return candidate
+	var best time.Time
+	for _, clock := range clocks {
+		hour, min := parseClock(clock)
+		candidate := time.Date(now.Year(), now.Month(), now.Day(), hour, min, 0, 0, loc)
+		if !candidate.After(now) {
+			candidate = candidate.AddDate(0, 0, 1)
+		}
+		if best.IsZero() || candidate.Before(best) {
+			best = candidate
+		}
+	}
+	return best
 }
 
-func nextWeekly(weekdayName, clock string, now time.Time, loc *time.Location) (time.Time, error) {
+func nextWeekly(weekdayName string, clocks []string, now time.Time, loc *time.Location) (time.Time, error) {
 	target, ok := parseWeekday(weekdayName)
 	if !ok {
 		return time.Time{}, fmt.Errorf("invalid weekday: %s", weekdayName)
 	}
-	hour, min := parseClock(clock)
+	if len(clocks) == 0 {
+		clocks = []string{""}
+	}
 	delta := (int(target) - int(now.Weekday()) + 7) % 7
-	candidate := time.Date(now.Year(), now.Month(), now.Day(), hour, min, 0, 0, loc).AddDate(0, 0, delta)
-	if !candidate.After(now) {
-		candidate = candidate.AddDate(0, 0, 7)
+	var best time.Time
+	for _, clock := range clocks {
+		hour, min := parseClock(clock)
+		candidate := time.Date(now.Year(), now.Month(), now.Day(), hour, min, 0, 0, loc).AddDate(0, 0, delta)
+		if !candidate.After(now) {
+			candidate = candidate.AddDate(0, 0, 7)
+		}
+		if best.IsZero() || candidate.Before(best) {
+			best = candidate
+		}
 	}
-	return candidate, nil
+	return best, nil
+}
+
+// nextMonthly runs on the last day of months shorter than day.
+func nextMonthly(day int, clock string, now time.Time, loc *time.Location) (time.Time, error) {
+	if (day < 1 || day > 31) && day != LastDayOfMonth {
+		return time.Time{}, fmt.Errorf("invalid day of month: %d", day)
+	}
+	hour, min := parseClock(clock)
+	for i := 0; i < 2; i++ {
+		month := time.Date(now.Year(), now.Month()+time.Month(i), 1, 0, 0, 0, 0, loc)
+		candidate := time.Date(month.Year(), month.Month(), MonthDay(day, month.Year(), month.Month()), hour, min, 0, 0, loc)
+		if candidate.After(now) {
+			return candidate, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("no monthly run found")
+}
+
+// nextCron walks forward a month, day, hour or minute at a time, whichever
+// field fails to match, so sparse expressions stay cheap.
+func nextCron(expr string, now time.Time, loc *time.Location) (time.Time, error) {
+	spec, err := parseCron(expr)
+	if err != nil {
+		return time.Time{}, err
+	}
+	t := time.Date(now.Year(), now.Month(), now.Day(), now.Hour(), now.Minute()+1, 0, 0, loc)
+	limit := now.AddDate(5, 0, 0)
+	for t.Before(limit) {
+		switch {
+		case spec.months&(1<<uint(t.Month())) == 0:
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, loc)
+		case !spec.matchesDay(t):
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, loc)
+		case spec.hours&(1<<uint(t.Hour())) == 0:
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, loc)
+		case spec.minutes&(1<<uint(t.Minute())) == 0:
+			t = t.Add(time.Minute)
+		default:
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("cron %q never matches", expr)
+}
+
+// MonthDay clamps day to the last day of the month, which LastDayOfMonth
+// always is.
+func MonthDay(day, year int, month time.Month) int {
+	last := time.Date(year, month+1, 0, 0, 0, 0, 0, time.UTC).Day()
+	if day > last || day == LastDayOfMonth {
+		return last
+	}
+	return day
+}
+
+// MonthDayLabel is "last day" for LastDayOfMonth and "5th" otherwise.
+func MonthDayLabel(day int) string {
+	if day == LastDayOfMonth {
+		return "last day"
+	}
+	return OrdinalDay(day)
+}
+
+func OrdinalDay(day int) string {
+	suffix := "th"
+	if day%100 < 11 || day%100 > 13 {
+		switch day % 10 {
+		case 1:
+			suffix = "st"
+		case 2:
+			suffix = "nd"
+		case 3:
+			suffix = "rd"
+		}
+	}
+	return fmt.Sprintf("%d%s", day, suffix)
+}
+
+func ScheduleTimes(schedule Schedule) []string {
+	if len(schedule.Times) > 0 {
+		return schedule.Times
+	}
+	if schedule.Time != "" {
+		return []string{schedule.Time}
+	}
+	return nil
+}
+
+func ParseTimes(value string) ([]string, error) {
+	seen := make(map[string]struct{})
+	times := make([]string, 0, 2)
+	for _, part := range strings.Split(value, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		parsed, err := time.Parse("15:04", part)
+		if err != nil {
+			return nil, fmt.Errorf("invalid time: %s", part)
+		}
+		clock := parsed.Format("15:04")
+		if _, ok := seen[clock]; ok {
+			continue
+		}
+		seen[clock] = struct{}{}
+		times = append(times, clock)
+	}
+	if len(times) == 0 {
+		return nil, fmt.Errorf("time required")
+	}
+	sort.Strings(times)
+	return times, nil
 }
 
 func parseClock(clock string) (int, int) {
@@ -119,7 +320,62 @@ func WeekdayNumber(name string) (int, bool) {
 	return int(day), true
 }
 
+func ScheduleLabel(entry ScheduleEntry) string {
+	switch entry.Schedule.Type {
+	case "daily":
+		if times := ScheduleTimes(entry.Schedule); len(times) > 0 {
+			return fmt.Sprintf("Daily %s", strings.Join(times, ", "))
+		}
+		return "Daily"
+	case "weekly":
+		times := ScheduleTimes(entry.Schedule)
+		if len(times) > 0 && entry.Schedule.Weekday != "" {
+			return fmt.Sprintf("Weekly %s %s", entry.Schedule.Weekday, strings.Join(times, ", "))
+		}
+		if entry.Schedule.Weekday != "" {
+			return fmt.Sprintf("Weekly %s", entry.Schedule.Weekday)
+		}
+		return "Weekly"
+	case "monthly":
+		label := "Monthly"
+		if entry.Schedule.Day == LastDayOfMonth {
+			label = "Monthly on the last day"
+		} else if entry.Schedule.Day > 0 {
+			label = fmt.Sprintf("Monthly on the %s", OrdinalDay(entry.Schedule.Day))
+			if entry.Schedule.Day > 28 {
+				label += " (or last day)"
+			}
+		}
+		if entry.Schedule.Time != "" {
+			label = fmt.Sprintf("%s %s", label, entry.Schedule.Time)
+		}
+		return label
+	case "once":
+		if entry.Schedule.Date != "" && entry.Schedule.Time != "" {
+			return fmt.Sprintf("Once %s %s", entry.Schedule.Date, entry.Schedule.Time)
+		}
+		return "Once"
+	case "cron":
+		return fmt.Sprintf("Cron %s", strings.TrimSpace(entry.Schedule.Cron))
+	case "interval":
+		return fmt.Sprintf("Every %s", IntervalLabel(entry.Schedule.IntervalMinutes))
+	case "sun":
+		event := "Sunset"
+		if strings.EqualFold(entry.Schedule.Event, "sunrise") {
+			event = "Sunrise"
+		}
+		return fmt.Sprintf("%s (%.2f, %.2f)", event, entry.Schedule.Latitude, entry.Schedule.Longitude)
+	case "login":
+		return "At login"
+	default:
+		return "Schedule"
+	}
+}
+
 func FormatPMSet(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
 	return t.Format("01/02/06 15:04:05")
 }
 
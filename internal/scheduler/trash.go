@@ -0,0 +1,99 @@
+package scheduler
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"time"
+)
+
+const (
+	MaxTrashEntries = 20
+	TrashRetention  = 30 * 24 * time.Hour
+)
+
+type TrashEntry struct {
+	DeletedAt time.Time     `json:"deletedAt"`
+	Schedule  ScheduleEntry `json:"schedule"`
+}
+
+// LoadTrash returns deleted schedules, newest first, dropping expired ones.
+func (s *Store) LoadTrash(now time.Time) ([]TrashEntry, error) {
+	file, err := os.Open(s.Trash)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return []TrashEntry{}, nil
+		}
+		return nil, fmt.Errorf("read trash: %w", err)
+	}
+	defer file.Close()
+
+	var entries []TrashEntry
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 4*1024*1024)
+	for scanner.Scan() {
+		var entry TrashEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			continue
+		}
+		if now.Sub(entry.DeletedAt) > TrashRetention {
+			continue
+		}
+		entries = append(entries, entry)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("read trash: %w", err)
+	}
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].DeletedAt.After(entries[j].DeletedAt)
+	})
+	return entries, nil
+}
+
+func (s *Store) TrashSchedule(entry ScheduleEntry, now time.Time) error {
+	entries, err := s.LoadTrash(now)
+	if err != nil {
+		return err
+	}
+	entries = append([]TrashEntry{{DeletedAt: now, Schedule: entry}}, entries...)
+	return s.saveTrash(entries)
+}
+
+func (s *Store) RemoveFromTrash(id string, now time.Time) error {
+	entries, err := s.LoadTrash(now)
+	if err != nil {
+		return err
+	}
+	kept := entries[:0]
+	for _, entry := range entries {
+		if entry.Schedule.ID != id {
+			kept = append(kept, entry)
+		}
+	}
+	return s.saveTrash(kept)
+}
+
+func (s *Store) saveTrash(entries []TrashEntry) error {
+	if err := s.Ensure(); err != nil {
+		return err
+	}
+	if len(entries) > MaxTrashEntries {
+		entries = entries[:MaxTrashEntries]
+	}
+	var data []byte
+	for _, entry := range entries {
+		line, err := json.Marshal(entry)
+		if err != nil {
+			return fmt.Errorf("encode trash: %w", err)
+		}
+		data = append(data, line...)
+		data = append(data, '\n')
+	}
+	tmp := s.Trash + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return fmt.Errorf("write trash: %w", err)
+	}
+	return os.Rename(tmp, s.Trash)
+}
@@ -3,33 +3,68 @@ package scheduler
 import "time"
 
 type ScheduleEntry struct {
-	ID             string    `json:"id"`
-	ProjectPath    string    `json:"projectPath"`
-	SessionID      string    `json:"sessionId,omitempty"`
-	SessionPath    string    `json:"sessionPath,omitempty"`
-	NewSession     bool      `json:"newSession"`
-	Model          string    `json:"model"`
-	PermissionMode string    `json:"permissionMode,omitempty"`
-	Prompt         string    `json:"prompt"`
-	Schedule       Schedule  `json:"schedule"`
-	Timezone       string    `json:"timezone"`
-	CreatedAt      time.Time `json:"createdAt"`
-	UpdatedAt      time.Time `json:"updatedAt"`
-	NextRun        time.Time `json:"nextRun"`
-	WakeTime       string    `json:"wakeTime"`
-	BinaryPath     string    `json:"binaryPath"`
-	User           string    `json:"user"`
-	UID            int       `json:"uid"`
-	GID            int       `json:"gid"`
-	HomeDir        string    `json:"homeDir"`
-	PathEnv        string    `json:"pathEnv"`
+	ID                string            `json:"id"`
+	ProjectPath       string            `json:"projectPath"`
+	SessionID         string            `json:"sessionId,omitempty"`
+	SessionPath       string            `json:"sessionPath,omitempty"`
+	NewSession        bool              `json:"newSession"`
+	ForkSession       bool              `json:"forkSession,omitempty"`
+	Model             string            `json:"model"`
+	PermissionMode    string            `json:"permissionMode,omitempty"`
+	Prompt            string            `json:"prompt"`
+	PreCommand        string            `json:"preCommand,omitempty"`
+	PostCommand       string            `json:"postCommand,omitempty"`
+	Env               map[string]string `json:"env,omitempty"`
+	Description       string            `json:"description,omitempty"`
+	Tags              []string          `json:"tags,omitempty"`
+	GroupID           string            `json:"groupId,omitempty"`
+	GroupName         string            `json:"groupName,omitempty"`
+	Notify            string            `json:"notify,omitempty"`
+	NotifySound       string            `json:"notifySound,omitempty"`
+	Schedule          Schedule          `json:"schedule"`
+	Timezone          string            `json:"timezone"`
+	CreatedAt         time.Time         `json:"createdAt"`
+	UpdatedAt         time.Time         `json:"updatedAt"`
+	NextRun           time.Time         `json:"nextRun"`
+	PausedUntil       time.Time         `json:"pausedUntil,omitempty"`
+	StartAt           time.Time         `json:"startAt,omitempty"`
+	Disabled          bool              `json:"disabled,omitempty"`
+	MinBatteryPercent int               `json:"minBatteryPercent,omitempty"`
+	RequireAC         bool              `json:"requireAC,omitempty"`
+	RequireNetwork    bool              `json:"requireNetwork,omitempty"`
+	RequireDirty      bool              `json:"requireDirty,omitempty"`
+	Retries           int               `json:"retries,omitempty"`
+	RetryDelay        string            `json:"retryDelay,omitempty"`
+	NetworkHost       string            `json:"networkHost,omitempty"`
+	OutputFormat      string            `json:"outputFormat,omitempty"`
+	LowPriority       bool              `json:"lowPriority,omitempty"`
+	ProcessType       string            `json:"processType,omitempty"`
+	WakeTime          string            `json:"wakeTime"`
+	BinaryPath        string            `json:"binaryPath"`
+	ClaudeBinary      string            `json:"claudeBinary,omitempty"`
+	User              string            `json:"user"`
+	UID               int               `json:"uid"`
+	GID               int               `json:"gid"`
+	HomeDir           string            `json:"homeDir"`
+	HomeOverride      string            `json:"homeOverride,omitempty"`
+	PathEnv           string            `json:"pathEnv"`
 }
 
+// LastDayOfMonth as a monthly Schedule.Day runs on each month's final day.
+const LastDayOfMonth = -1
+
 type Schedule struct {
-	Type    string `json:"type"`
-	Date    string `json:"date,omitempty"`
-	Time    string `json:"time,omitempty"`
-	Weekday string `json:"weekday,omitempty"`
+	Type            string   `json:"type"`
+	Date            string   `json:"date,omitempty"`
+	Time            string   `json:"time,omitempty"`
+	Times           []string `json:"times,omitempty"`
+	Weekday         string   `json:"weekday,omitempty"`
+	Day             int      `json:"day,omitempty"`
+	Cron            string   `json:"cron,omitempty"`
+	IntervalMinutes int      `json:"intervalMinutes,omitempty"`
+	Event           string   `json:"event,omitempty"`
+	Latitude        float64  `json:"latitude,omitempty"`
+	Longitude       float64  `json:"longitude,omitempty"`
 }
 
 type LogEntry struct {
@@ -37,7 +72,13 @@ type LogEntry struct {
 	ScheduleID    string    `json:"scheduleId"`
 	RanAt         time.Time `json:"ranAt"`
 	Status        string    `json:"status"`
+	Attempt       int       `json:"attempt,omitempty"`
 	ExitCode      int       `json:"exitCode"`
+	DurationMs    int64     `json:"durationMs,omitempty"`
+	ResultSummary string    `json:"resultSummary,omitempty"`
+	InputTokens   int       `json:"inputTokens,omitempty"`
+	OutputTokens  int       `json:"outputTokens,omitempty"`
+	CostUSD       float64   `json:"costUsd,omitempty"`
 	Error         string    `json:"error,omitempty"`
 	PromptPreview string    `json:"promptPreview"`
 	Model         string    `json:"model"`
@@ -1,10 +1,14 @@
 package tui
 
 import (
+	"bytes"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
 	"os"
+	"os/exec"
+	"sort"
 	"strings"
 	"time"
 
@@ -27,6 +31,12 @@ type Input struct {
 	TokenReady  bool
 	TokenErr    string
 	SetupCmd    string
+	// From the config; an empty value keeps the built-in default.
+	DefaultModel      string
+	DefaultPermission string
+	// QuickDelete deletes from the schedule list with d pressed twice
+	// instead of the confirm screen.
+	QuickDelete bool
 }
 
 type ActionKind int
@@ -36,32 +46,65 @@ const (
 	ActionSchedule
 	ActionEdit
 	ActionDelete
+	ActionPause
+	ActionReplace
 	ActionQuit
 )
 
 type Action struct {
-	Kind       ActionKind
-	Draft      *Draft
-	ScheduleID string
+	Kind        ActionKind
+	Draft       *Draft
+	Entry       *scheduler.ScheduleEntry
+	ScheduleID  string
+	PausedUntil time.Time
+	GroupID     string
 }
 
 type Draft struct {
-	ProjectPath string
-	SessionID   string
-	SessionPath string
-	NewSession  bool
-	Model       string
-	Permission  string
-	Prompt      string
-	Schedule    Schedule
+	ProjectPath  string
+	SessionID    string
+	SessionPath  string
+	NewSession   bool
+	ForkSession  bool
+	Model        string
+	Permission   string
+	Prompt       string
+	PreCommand   string
+	PostCommand  string
+	Env          map[string]string
+	Description  string
+	Tags         []string
+	Group        string
+	Notify       string
+	NotifySound  string
+	HomeDir      string
+	ClaudeBinary string
+	MinBattery   int
+	RequireAC    bool
+	NetworkHost  string
+	RequireDirty bool
+	Retries      int
+	RetryDelay   string
+	JSONOutput   bool
+	LowPriority  bool
+	ProcessType  string
+	Schedule     Schedule
 }
 
 type Schedule struct {
-	Type     string
-	Date     string
-	Time     string
-	Weekday  string
-	Timezone string
+	Type      string
+	Date      string
+	Time      string
+	Times     []string
+	Weekday   string
+	Day       int
+	Cron      string
+	Interval  int
+	StartDate string
+	Event     string
+	Latitude  float64
+	Longitude float64
+	Timezone  string
 }
 
 type stage int
@@ -70,22 +113,45 @@ const (
 	stageMain stage = iota
 	stageProjects
 	stageSessions
+	stageResumeMode
 	stageModels
 	stagePermissionMode
 	stagePrompt
+	stageDescription
+	stageTags
 	stageScheduleType
 	stageScheduleDate
 	stageScheduleWeekday
 	stageScheduleTime
+	stageSunEvent
+	stageSunLocation
 	stageSetupToken
 	stageScheduleList
 	stageLogs
 	stageLogDetail
 	stageConfirmDelete
+	stageScheduleDetail
+	stagePause
+	stageNextRun
+	stageNotify
+	stageStats
+	stageProjectPath
+	stageScheduleDay
+	stageScheduleCron
+	stageScheduleInterval
+	stageStartDate
+	stagePreCommand
+	stagePostCommand
+	stageEnv
+	stageConfirmEdit
+	stageCustomModel
+	stageLogTail
 )
 
 var ErrUserQuit = errors.New("user quit")
 
+var errTimeInPast = errors.New("time is in the past")
+
 func Run(input Input) (Action, error) {
 	m := newModel(input)
 	program := tea.NewProgram(m, tea.WithAltScreen())
@@ -117,24 +183,34 @@ const (
 	itemProject
 	itemSession
 	itemNewSession
+	itemResumeMode
 	itemModel
 	itemPermissionMode
 	itemScheduleType
 	itemWeekday
+	itemSunEvent
 	itemSchedule
 	itemLog
 	itemConfirm
+	itemPause
+	itemNotify
+	itemManualPath
+	itemMonthDay
+	itemCustomModel
 )
 
 type listItem struct {
 	title  string
 	meta   string
+	next   time.Time
 	detail string
 	extra  string
 	filter string
 	kind   itemKind
 	index  int
 	pinned bool
+	tags   []string
+	group  string
 }
 
 type model struct {
@@ -147,8 +223,12 @@ type model struct {
 	sessions      []app.Session
 	selectedSess  *app.Session
 	selectedNew   bool
+	selectedFork  bool
 	selectedModel app.ModelOption
 	selectedPerm  string
+	defaultModel  app.ModelOption
+	defaultPerm   string
+	selectedNote  string
 	models        []app.ModelOption
 	claudeReady   bool
 	installCmd    string
@@ -157,21 +237,62 @@ type model struct {
 	setupCmd      string
 
 	promptText         string
+	preCommandText     string
+	postCommandText    string
+	envText            string
+	descriptionText    string
+	tagsText           string
+	shellWarned        string
 	schedule           Schedule
 	inputError         string
 	editID             string
+	duplicate          *scheduler.ScheduleEntry
+	confirmReturn      stage
 	pendingDel         *scheduler.ScheduleEntry
+	pendingPause       *scheduler.ScheduleEntry
+	pendingGroup       string
+	clearingLogs       bool
 	logDetailIndex     int
 	logDetailOutput    string
+	tailEntry          scheduler.LogEntry
+	tailOutput         string
+	tailSeq            int
 	logDetailOutputErr string
+	logErrorExpanded   bool
+	logScheduleID      string
+	logSessionsOnly    bool
+	logNote            string
+	logNoteSeq         int
+	contentHits        map[string]map[string]bool
+	quickDelete        bool
+	armedDelete        string
+	armedSeq           int
+	listTicking        bool
+	detailScheduleID   string
+	editOnly           stage
+	nextRunID          string
+	statsWindow        int
+	manualProject      bool
+	converting         bool
 	tokenVerifying     bool
 	tokenSpinnerIndex  int
 
 	searchInput textinput.Model
 	promptInput textarea.Model
+	envInput    textarea.Model
 	tokenInput  textinput.Model
 	dateInput   textinput.Model
 	timeInput   textinput.Model
+	locInput    textinput.Model
+	cronInput   textinput.Model
+	startInput  textinput.Model
+	everyInput  textinput.Model
+	descInput   textinput.Model
+	hookInput   textinput.Model
+	tagsInput   textinput.Model
+	nextInput   textinput.Model
+	pathInput   textinput.Model
+	modelInput  textinput.Model
 
 	items  []listItem
 	all    []listItem
@@ -213,6 +334,70 @@ func newModel(input Input) model {
 	timeInput.CharLimit = 5
 	timeInput.Blur()
 
+	descInput := textinput.New()
+	descInput.Prompt = ""
+	descInput.Placeholder = "e.g. Nightly changelog"
+	descInput.CharLimit = 60
+	descInput.Blur()
+
+	hookInput := textinput.New()
+	hookInput.Prompt = ""
+	hookInput.CharLimit = 500
+	hookInput.Blur()
+
+	envInput := textarea.New()
+	envInput.Placeholder = "NODE_ENV=production"
+	envInput.ShowLineNumbers = false
+	envInput.CharLimit = 0
+	envInput.Blur()
+
+	tagsInput := textinput.New()
+	tagsInput.Prompt = ""
+	tagsInput.Placeholder = "e.g. work, reports"
+	tagsInput.CharLimit = 128
+	tagsInput.Blur()
+
+	locInput := textinput.New()
+	locInput.Prompt = ""
+	locInput.Placeholder = "latitude, longitude"
+	locInput.CharLimit = 64
+	locInput.Blur()
+
+	cronInput := textinput.New()
+	cronInput.Prompt = ""
+	cronInput.Placeholder = "e.g. 0 9 * * 1-5"
+	cronInput.CharLimit = 128
+	cronInput.Blur()
+
+	startInput := textinput.New()
+	startInput.Prompt = ""
+	startInput.Placeholder = "YYYY-MM-DD, blank to start now"
+	startInput.CharLimit = 10
+	startInput.Blur()
+
+	everyInput := textinput.New()
+	everyInput.Prompt = ""
+	everyInput.Placeholder = "e.g. 4h or 90m"
+	everyInput.CharLimit = 16
+	everyInput.Blur()
+
+	pathInput := textinput.New()
+	pathInput.Prompt = ""
+	pathInput.Placeholder = "~/code/new-project"
+	pathInput.CharLimit = 512
+	pathInput.Blur()
+
+	modelInput := textinput.New()
+	modelInput.Prompt = ""
+	modelInput.Placeholder = "claude-sonnet-4-5-20250929"
+	modelInput.CharLimit = 200
+	modelInput.Blur()
+
+	nextInput := textinput.New()
+	nextInput.Prompt = ""
+	nextInput.CharLimit = 64
+	nextInput.Blur()
+
 	tokenInput := textinput.New()
 	tokenInput.Prompt = ""
 	tokenInput.Placeholder = "paste your setup token..."
@@ -226,7 +411,6 @@ func newModel(input Input) model {
 		schedules:          input.Schedules,
 		logs:               input.Logs,
 		models:             models,
-		selectedPerm:       "acceptEdits",
 		claudeReady:        input.ClaudeReady,
 		installCmd:         input.InstallCmd,
 		tokenReady:         input.TokenReady,
@@ -242,7 +426,28 @@ func newModel(input Input) model {
 		tokenInput:         tokenInput,
 		dateInput:          dateInput,
 		timeInput:          timeInput,
-	}
+		locInput:           locInput,
+		cronInput:          cronInput,
+		startInput:         startInput,
+		everyInput:         everyInput,
+		descInput:          descInput,
+		hookInput:          hookInput,
+		envInput:           envInput,
+		tagsInput:          tagsInput,
+		nextInput:          nextInput,
+		pathInput:          pathInput,
+		modelInput:         modelInput,
+	}
+	m.quickDelete = input.QuickDelete
+	m.defaultPerm = "acceptEdits"
+	if input.DefaultPermission != "" {
+		m.defaultPerm = input.DefaultPermission
+	}
+	if input.DefaultModel != "" {
+		m.defaultModel = m.findModel(input.DefaultModel)
+	}
+	m.selectedModel = m.defaultModel
+	m.selectedPerm = m.defaultPerm
 
 	if !m.tokenReady {
 		m.startSetupTokenStage()
@@ -258,7 +463,25 @@ func (m model) Init() tea.Cmd {
 	return nil
 }
 
+// Update keeps the schedule list's countdowns ticking while it is open; the
+// tick stops re-arming once another stage is showing.
 func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if _, ok := msg.(scheduleTickMsg); ok {
+		if m.stage != stageScheduleList {
+			m.listTicking = false
+			return m, nil
+		}
+		return m, scheduleTickCmd()
+	}
+	next, cmd := m.update(msg)
+	if m.stage == stageScheduleList && !m.listTicking {
+		m.listTicking = true
+		return next, tea.Batch(cmd, scheduleTickCmd())
+	}
+	return next, cmd
+}
+
+func (m *model) update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msgTyped := msg.(type) {
 	case tea.WindowSizeMsg:
 		m.width = msgTyped.Width
@@ -268,24 +491,92 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case tea.KeyMsg:
 		switch msgTyped.String() {
 		case "ctrl+c", "q":
+			// Shell commands and variables need a typeable q.
+			if msgTyped.String() == "q" && (m.stage == stagePreCommand || m.stage == stagePostCommand || m.stage == stageEnv || m.stage == stageCustomModel || m.listSearching()) {
+				break
+			}
 			m.err = ErrUserQuit
 			return m, tea.Quit
 		case "esc":
+			if m.listSearching() {
+				m.searchInput.Blur()
+				return m, nil
+			}
 			return m.handleBack()
 		}
+	case logTailMsg:
+		if m.stage != stageLogTail || msgTyped.seq != m.tailSeq {
+			return m, nil
+		}
+		if m.refreshTail() {
+			return m, logTailCmd(m.tailSeq)
+		}
+		return m, nil
+	case logNoteClearMsg:
+		if msgTyped.seq == m.logNoteSeq {
+			m.logNote = ""
+		}
+		return m, nil
+	case disarmDeleteMsg:
+		if msgTyped.seq == m.armedSeq {
+			m.armedDelete = ""
+		}
+		return m, nil
+	case contentSearchMsg:
+		if query, ok := m.sessionContentQuery(); ok && query == msgTyped.query {
+			if _, done := m.contentHits[m.contentKey(query)]; !done {
+				return m, searchSessionsCmd(m.contentKey(query), query, m.sessions)
+			}
+		}
+		return m, nil
+	case contentResultMsg:
+		if m.contentHits == nil {
+			m.contentHits = make(map[string]map[string]bool)
+		}
+		m.contentHits[msgTyped.key] = msgTyped.hits
+		if m.stage == stageSessions {
+			m.applyFilter()
+		}
+		return m, nil
+	case editorDoneMsg:
+		m.finishEditJSON(msgTyped)
+		if m.action.Kind == ActionReplace {
+			return m, tea.Quit
+		}
+		return m, nil
 	}
 
 	switch m.stage {
 	case stagePrompt:
 		return m.updatePrompt(msg)
-	case stageScheduleDate, stageScheduleTime:
+	case stageScheduleDate, stageScheduleTime, stageSunLocation, stageScheduleCron, stageScheduleInterval, stageStartDate:
 		return m.updateScheduleInput(msg)
 	case stageSetupToken:
 		return m.updateSetupToken(msg)
-	case stageProjects, stageSessions, stageModels, stagePermissionMode, stageScheduleType, stageScheduleWeekday, stageMain, stageScheduleList, stageLogs, stageConfirmDelete:
+	case stagePreCommand, stagePostCommand:
+		return m.updateHookCommand(msg)
+	case stageEnv:
+		return m.updateEnv(msg)
+	case stageDescription:
+		return m.updateDescription(msg)
+	case stageTags:
+		return m.updateTags(msg)
+	case stageProjects, stageSessions, stageResumeMode, stageModels, stagePermissionMode, stageNotify, stageScheduleType, stageScheduleWeekday, stageScheduleDay, stageSunEvent, stageMain, stageScheduleList, stageLogs, stageConfirmDelete, stagePause:
 		return m.updateList(msg)
 	case stageLogDetail:
 		return m.updateLogDetail(msg)
+	case stageScheduleDetail:
+		return m.updateScheduleDetail(msg)
+	case stageNextRun:
+		return m.updateNextRun(msg)
+	case stageConfirmEdit:
+		return m.updateConfirmEdit(msg)
+	case stageCustomModel:
+		return m.updateCustomModel(msg)
+	case stageStats:
+		return m.updateStats(msg)
+	case stageProjectPath:
+		return m.updateProjectPath(msg)
 	default:
 		return m, nil
 	}
@@ -316,12 +607,57 @@ func (m model) View() string {
 	case stageScheduleTime:
 		m.renderScheduleTime(&b, lineWidth)
 		return b.String()
+	case stageSunLocation:
+		m.renderSunLocation(&b, lineWidth)
+		return b.String()
+	case stageScheduleCron:
+		m.renderScheduleCron(&b, lineWidth)
+		return b.String()
+	case stageScheduleInterval:
+		m.renderScheduleInterval(&b, lineWidth)
+		return b.String()
+	case stageStartDate:
+		m.renderStartDate(&b, lineWidth)
+		return b.String()
+	case stagePreCommand, stagePostCommand:
+		m.renderHookCommand(&b, lineWidth)
+		return b.String()
+	case stageEnv:
+		m.renderEnv(&b, lineWidth)
+		return b.String()
+	case stageDescription:
+		m.renderDescription(&b, lineWidth)
+		return b.String()
+	case stageTags:
+		m.renderTags(&b, lineWidth)
+		return b.String()
 	case stageSetupToken:
 		m.renderSetupToken(&b, lineWidth)
 		return b.String()
 	case stageLogDetail:
 		m.renderLogDetail(&b, lineWidth)
 		return b.String()
+	case stageScheduleDetail:
+		m.renderScheduleDetail(&b, lineWidth)
+		return b.String()
+	case stageNextRun:
+		m.renderNextRun(&b, lineWidth)
+		return b.String()
+	case stageConfirmEdit:
+		m.renderConfirmEdit(&b, lineWidth)
+		return b.String()
+	case stageCustomModel:
+		m.renderCustomModel(&b, lineWidth)
+		return b.String()
+	case stageLogTail:
+		m.renderLogTail(&b, lineWidth)
+		return b.String()
+	case stageStats:
+		m.renderStats(&b, lineWidth)
+		return b.String()
+	case stageProjectPath:
+		m.renderProjectPath(&b, lineWidth)
+		return b.String()
 	default:
 		m.renderList(&b, lineWidth)
 		return b.String()
@@ -338,6 +674,13 @@ func (m model) renderPrompt(b *strings.Builder, width int) {
 	if m.inputError != "" {
 		b.WriteString(renderLine(fmt.Sprintf("Error: %s", m.inputError), width))
 		b.WriteString("\n")
+	} else if m.shellWarned != "" && m.shellWarned == strings.TrimSpace(m.promptInput.Value()) {
+		b.WriteString(renderWrappedLines("Note: this looks like a shell command. It will be sent to Claude as a prompt, not run in a shell. Press ctrl+d again to continue.", width, len("Note: ")))
+		b.WriteString("\n")
+	}
+	if m.editOnly == stagePrompt {
+		b.WriteString("ctrl+d save | esc back | q quit\n")
+		return
 	}
 	b.WriteString("ctrl+d continue | esc back | q quit\n")
 }
@@ -369,13 +712,22 @@ func (m model) renderScheduleTime(b *strings.Builder, width int) {
 			b.WriteString(renderLine(fmt.Sprintf("Weekly on %s.", m.schedule.Weekday), width))
 			b.WriteString("\n")
 		}
+	case "monthly":
+		if m.schedule.Day != 0 {
+			b.WriteString(renderLine(fmt.Sprintf("Monthly on the %s.", scheduler.MonthDayLabel(m.schedule.Day)), width))
+			b.WriteString("\n")
+		}
 	case "once":
 		if m.schedule.Date != "" {
 			b.WriteString(renderLine(fmt.Sprintf("One-time on %s.", m.schedule.Date), width))
 			b.WriteString("\n")
 		}
 	}
-	b.WriteString(renderLine("Time (24-hour HH:MM):", width))
+	if m.allowsMultipleTimes() {
+		b.WriteString(renderLine("Times (24-hour HH:MM, comma-separated):", width))
+	} else {
+		b.WriteString(renderLine("Time (24-hour HH:MM):", width))
+	}
 	b.WriteString("\n")
 	b.WriteString(m.timeInput.View())
 	b.WriteString(clearLine)
@@ -387,6 +739,116 @@ func (m model) renderScheduleTime(b *strings.Builder, width int) {
 	b.WriteString("enter confirm | esc back | q quit\n")
 }
 
+func (m model) renderDescription(b *strings.Builder, width int) {
+	m.renderContextHeader(b, width)
+	b.WriteString(renderLine("Description (optional, shown as the notification title):", width))
+	b.WriteString("\n")
+	b.WriteString(m.descInput.View())
+	b.WriteString(clearLine)
+	b.WriteString("\n")
+	b.WriteString("enter continue | esc back | q quit\n")
+}
+
+func (m model) renderHookCommand(b *strings.Builder, width int) {
+	m.renderContextHeader(b, width)
+	if m.stage == stagePreCommand {
+		b.WriteString(renderLine("Shell command to run before the prompt (optional; if it fails, the run is aborted):", width))
+	} else {
+		b.WriteString(renderLine("Shell command to run after the prompt (optional; runs even if claude fails):", width))
+	}
+	b.WriteString("\n")
+	b.WriteString(m.hookInput.View())
+	b.WriteString(clearLine)
+	b.WriteString("\n")
+	b.WriteString("enter continue | esc back | ctrl+c quit\n")
+}
+
+func (m model) renderEnv(b *strings.Builder, width int) {
+	m.renderContextHeader(b, width)
+	b.WriteString(renderLine("Environment variables for claude (optional, one KEY=VALUE per line):", width))
+	b.WriteString("\n")
+	b.WriteString(m.envInput.View())
+	b.WriteString(clearLine)
+	b.WriteString("\n")
+	if m.inputError != "" {
+		b.WriteString(renderLine(fmt.Sprintf("Error: %s", m.inputError), width))
+		b.WriteString("\n")
+	}
+	b.WriteString("ctrl+d continue | esc back | ctrl+c quit\n")
+}
+
+func (m model) renderTags(b *strings.Builder, width int) {
+	m.renderContextHeader(b, width)
+	b.WriteString(renderLine("Tags (optional, comma-separated; filter the schedule list with #tag):", width))
+	b.WriteString("\n")
+	b.WriteString(m.tagsInput.View())
+	b.WriteString(clearLine)
+	b.WriteString("\n")
+	b.WriteString("enter continue | esc back | q quit\n")
+}
+
+func (m model) renderSunLocation(b *strings.Builder, width int) {
+	m.renderContextHeader(b, width)
+	b.WriteString(renderLine(fmt.Sprintf("Every day at %s.", sunEventLabel(m.schedule.Event)), width))
+	b.WriteString("\n")
+	b.WriteString(renderLine("Location (latitude, longitude):", width))
+	b.WriteString("\n")
+	b.WriteString(m.locInput.View())
+	b.WriteString(clearLine)
+	b.WriteString("\n")
+	if m.inputError != "" {
+		b.WriteString(renderLine(fmt.Sprintf("Error: %s", m.inputError), width))
+		b.WriteString("\n")
+	}
+	b.WriteString("enter confirm | esc back | q quit\n")
+}
+
+func (m model) renderScheduleCron(b *strings.Builder, width int) {
+	m.renderContextHeader(b, width)
+	b.WriteString(renderLine("Schedule: Cron.", width))
+	b.WriteString("\n")
+	b.WriteString(renderLine("Cron expression (minute hour day month weekday, or @daily, @weekly ...):", width))
+	b.WriteString("\n")
+	b.WriteString(m.cronInput.View())
+	b.WriteString(clearLine)
+	b.WriteString("\n")
+	if m.inputError != "" {
+		b.WriteString(renderLine(fmt.Sprintf("Error: %s", m.inputError), width))
+		b.WriteString("\n")
+	}
+	b.WriteString("enter confirm | esc back | q quit\n")
+}
+
+func (m model) renderStartDate(b *strings.Builder, width int) {
+	m.renderContextHeader(b, width)
+	b.WriteString(renderLine("Optional: don't start before this date (YYYY-MM-DD, blank to start now):", width))
+	b.WriteString("\n")
+	b.WriteString(m.startInput.View())
+	b.WriteString(clearLine)
+	b.WriteString("\n")
+	if m.inputError != "" {
+		b.WriteString(renderLine(fmt.Sprintf("Error: %s", m.inputError), width))
+		b.WriteString("\n")
+	}
+	b.WriteString("enter confirm | esc back | q quit\n")
+}
+
+func (m model) renderScheduleInterval(b *strings.Builder, width int) {
+	m.renderContextHeader(b, width)
+	b.WriteString(renderLine("Schedule: Every N hours / minutes, counted from when it's saved.", width))
+	b.WriteString("\n")
+	b.WriteString(renderLine("Repeat every (e.g. 4h, 90m, 1h30m):", width))
+	b.WriteString("\n")
+	b.WriteString(m.everyInput.View())
+	b.WriteString(clearLine)
+	b.WriteString("\n")
+	if m.inputError != "" {
+		b.WriteString(renderLine(fmt.Sprintf("Error: %s", m.inputError), width))
+		b.WriteString("\n")
+	}
+	b.WriteString("enter confirm | esc back | q quit\n")
+}
+
 func (m model) renderSetupToken(b *strings.Builder, width int) {
 	if m.tokenReady {
 		b.WriteString(renderLine("update setup token.", width))
@@ -443,7 +905,19 @@ func (m model) renderLogDetail(b *strings.Builder, width int) {
 	b.WriteString("\n")
 
 	status := "OK"
-	if entry.Status != "success" {
+	if entry.Status == "running" {
+		status = "RUNNING"
+	} else if entry.Status == "locked" {
+		status = "KEYCHAIN LOCKED"
+	} else if entry.Status == "unknown" {
+		status = "UNKNOWN"
+	} else if entry.Status == "paused" {
+		status = "PAUSED (skipped)"
+	} else if entry.Status == "cancelled" {
+		status = "CANCELLED"
+	} else if entry.Status == "skipped" {
+		status = "SKIPPED"
+	} else if entry.Status != "success" {
 		status = "ERROR"
 	}
 	b.WriteString(renderLine(fmt.Sprintf("Status: %s", status), width))
@@ -462,10 +936,18 @@ func (m model) renderLogDetail(b *strings.Builder, width int) {
 	}
 	b.WriteString(renderLine(fmt.Sprintf("Ran: %s", ranLabel), width))
 	b.WriteString("\n")
+	if entry.Attempt > 0 {
+		b.WriteString(renderLine(fmt.Sprintf("Attempt: %d", entry.Attempt), width))
+		b.WriteString("\n")
+	}
+	if entry.DurationMs > 0 {
+		b.WriteString(renderLine(fmt.Sprintf("Duration: %s", formatRunDuration(time.Duration(entry.DurationMs)*time.Millisecond)), width))
+		b.WriteString("\n")
+	}
 
 	schedule, hasSchedule := m.findSchedule(entry.ScheduleID)
 	if hasSchedule {
-		b.WriteString(renderLine(fmt.Sprintf("Schedule: %s", formatScheduleLabel(schedule)), width))
+		b.WriteString(renderLine(fmt.Sprintf("Schedule: %s", scheduler.ScheduleLabel(schedule)), width))
 		b.WriteString("\n")
 		if added := formatDetailTime(schedule.CreatedAt, now); added != "" {
 			b.WriteString(renderLine(fmt.Sprintf("Added: %s", added), width))
@@ -491,7 +973,7 @@ func (m model) renderLogDetail(b *strings.Builder, width int) {
 		project = schedule.ProjectPath
 	}
 	if project != "" {
-		b.WriteString(renderWrappedPath("Project: ", app.HumanizePath(project), width))
+		b.WriteString(renderWrappedPath("Project: ", app.DisplayProjectPath(project), width))
 		b.WriteString("\n")
 	}
 
@@ -504,6 +986,10 @@ func (m model) renderLogDetail(b *strings.Builder, width int) {
 		b.WriteString("\n")
 	}
 
+	if entry.ResultSummary != "" {
+		b.WriteString(renderWrappedLines(fmt.Sprintf("Result: %s", entry.ResultSummary), width, len("Result: ")))
+		b.WriteString("\n")
+	}
 	if entry.Status != "success" && entry.OutputPath != "" {
 		b.WriteString(renderLine("Output:", width))
 		b.WriteString("\n")
@@ -519,7 +1005,7 @@ func (m model) renderLogDetail(b *strings.Builder, width int) {
 		}
 	}
 	if entry.SessionID != "" {
-		projectPath := m.logProjectPath(entry)
+		projectPath := app.ProjectCWD(m.logProjectPath(entry))
 		if expanded, err := app.ExpandHome(projectPath); err == nil && expanded != "" {
 			projectPath = expanded
 		}
@@ -530,133 +1016,499 @@ func (m model) renderLogDetail(b *strings.Builder, width int) {
 		b.WriteString(renderWrappedLines(fmt.Sprintf("Resume: %s", resumeCmd), width, len("Resume: ")))
 		b.WriteString("\n")
 	}
+	if m.inputError != "" {
+		b.WriteString(renderLine(fmt.Sprintf("Error: %s", m.inputError), width))
+		b.WriteString("\n")
+	}
 	b.WriteString("\n")
 	b.WriteString(m.footerHint())
 	b.WriteString("\n")
 }
 
-func (m model) renderList(b *strings.Builder, width int) {
-	switch m.stage {
-	case stageMain:
-		b.WriteString(renderLine("What would you like to do?", width))
+func (m model) renderScheduleDetail(b *strings.Builder, width int) {
+	entry, ok := m.findSchedule(m.detailScheduleID)
+	if !ok {
+		b.WriteString(renderLine("Schedule not found.", width))
 		b.WriteString("\n")
-	case stageProjects:
-		b.WriteString(renderLine("Select a project to continue.", width))
+		b.WriteString(m.footerHint())
 		b.WriteString("\n")
-	case stageSessions:
-		b.WriteString(renderLine(fmt.Sprintf("Project: %s", m.projectLabel()), width))
+		return
+	}
+
+	b.WriteString(renderLine("Schedule details.", width))
+	b.WriteString("\n")
+	b.WriteString(renderLine(fmt.Sprintf("Schedule: %s", scheduler.ScheduleLabel(entry)), width))
+	b.WriteString("\n")
+	if entry.Description != "" {
+		b.WriteString(renderLine(fmt.Sprintf("Description: %s", entry.Description), width))
 		b.WriteString("\n")
-		b.WriteString(renderLine("Select a session to resume (or start a new one).", width))
+	}
+	if entry.PreCommand != "" {
+		b.WriteString(renderLine(fmt.Sprintf("Before: %s", entry.PreCommand), width))
 		b.WriteString("\n")
-	case stageModels:
-		b.WriteString(renderLine(fmt.Sprintf("Project: %s", m.projectLabel()), width))
+	}
+	if entry.PostCommand != "" {
+		b.WriteString(renderLine(fmt.Sprintf("After: %s", entry.PostCommand), width))
 		b.WriteString("\n")
-		b.WriteString(renderLine(fmt.Sprintf("Session: %s", m.sessionLabel()), width))
+	}
+	if len(entry.Env) > 0 {
+		b.WriteString(renderLine(fmt.Sprintf("Env: %s", strings.Join(scheduler.EnvKeys(entry.Env), ", ")), width))
 		b.WriteString("\n")
-		b.WriteString(renderLine("Select a Claude model.", width))
+	}
+	if entry.GroupID != "" {
+		b.WriteString(renderLine(fmt.Sprintf("Group: %s", scheduler.GroupLabel(entry)), width))
 		b.WriteString("\n")
-	case stagePermissionMode:
-		m.renderContextHeader(b, width)
-		b.WriteString(renderLine("Select a permission mode.", width))
+	}
+	if len(entry.Tags) > 0 {
+		b.WriteString(renderLine(fmt.Sprintf("Tags: %s", scheduler.FormatTags(entry.Tags)), width))
 		b.WriteString("\n")
-	case stageScheduleType:
-		m.renderContextHeader(b, width)
-		b.WriteString(renderLine("Select when to run it.", width))
+	}
+
+	now := time.Now()
+	if entry.Disabled {
+		b.WriteString(renderLine("Status: disabled (no launchd job or wake; press e in the list to enable)", width))
 		b.WriteString("\n")
-	case stageScheduleWeekday:
-		m.renderContextHeader(b, width)
-		b.WriteString(renderLine("Schedule: Weekly.", width))
+	}
+	if entry.PausedUntil.After(now) {
+		b.WriteString(renderLine(fmt.Sprintf("Paused until: %s", formatDetailTime(entry.PausedUntil, now)), width))
 		b.WriteString("\n")
-		b.WriteString(renderLine("Select the day of week.", width))
+	}
+	if added := formatDetailTime(entry.CreatedAt, now); added != "" {
+		b.WriteString(renderLine(fmt.Sprintf("Added: %s", added), width))
 		b.WriteString("\n")
-	case stageScheduleList:
-		b.WriteString(renderLine("Scheduled prompts.", width))
+	}
+	if guard := scheduler.BatteryGuardLabel(entry); guard != "" {
+		b.WriteString(renderLine(fmt.Sprintf("Battery: %s", guard), width))
 		b.WriteString("\n")
-	case stageLogs:
-		b.WriteString(renderLine("Run logs.", width))
+	}
+	if entry.RequireNetwork {
+		b.WriteString(renderLine(fmt.Sprintf("Network: skip when %s is unreachable", scheduler.NetworkHostLabel(entry)), width))
 		b.WriteString("\n")
-	case stageConfirmDelete:
-		if m.pendingDel != nil {
-			b.WriteString(renderLine("Delete scheduled prompt?", width))
-			b.WriteString("\n")
-			b.WriteString(renderLine(fmt.Sprintf("%s", scheduler.Preview(m.pendingDel.Prompt, 80)), width))
-			b.WriteString("\n")
-		}
 	}
-
-	if m.projectsErr != nil && m.stage == stageMain {
-		b.WriteString(renderLine(fmt.Sprintf("Notice: %s", m.projectsErr.Error()), width))
+	if entry.RequireDirty {
+		b.WriteString(renderLine("Condition: only with uncommitted changes", width))
 		b.WriteString("\n")
 	}
-	if m.inputError != "" && (m.stage == stageMain || m.stage == stageLogs) {
-		b.WriteString(renderLine(fmt.Sprintf("Error: %s", m.inputError), width))
+	if retries := scheduler.RetryLabel(entry); retries != "" {
+		b.WriteString(renderLine(fmt.Sprintf("Retries: %s", retries), width))
 		b.WriteString("\n")
 	}
-
-	if m.usesSearch() {
-		b.WriteString(searchLabel)
-		b.WriteString(m.searchInput.View())
-		b.WriteString(clearLine)
+	if start := scheduler.StartDate(entry, time.Now()); start != "" {
+		b.WriteString(renderLine(fmt.Sprintf("Starts: %s", start), width))
 		b.WriteString("\n")
 	}
-	b.WriteString(strings.Repeat("-", max(10, min(width, 60))))
+	if entry.LowPriority {
+		b.WriteString(renderLine("Priority: low (background)", width))
+		b.WriteString("\n")
+	} else if processType := scheduler.ProcessTypeLabel(entry); processType != "" {
+		b.WriteString(renderLine(fmt.Sprintf("Process type: %s", processType), width))
+		b.WriteString("\n")
+	}
+	if entry.Model != "" {
+		b.WriteString(renderLine(fmt.Sprintf("Model: %s", entry.Model), width))
+		b.WriteString("\n")
+	}
+	if entry.PermissionMode != "" {
+		b.WriteString(renderLine(fmt.Sprintf("Permission: %s", entry.PermissionMode), width))
+		b.WriteString("\n")
+	}
+	if entry.ProjectPath != "" {
+		b.WriteString(renderWrappedPath("Project: ", app.DisplayProjectPath(entry.ProjectPath), width))
+		b.WriteString("\n")
+	}
+	b.WriteString(renderWrappedPath("Runs in: ", scheduler.WorkDirLabel(entry), width))
 	b.WriteString("\n")
-
-	if len(m.items) == 0 {
-		empty := "No matches."
-		if m.stage == stageScheduleList {
-			empty = "No active schedules."
-		} else if m.stage == stageLogs {
-			empty = "No logs yet."
-		}
-		b.WriteString(renderLine(empty, width))
+	if entry.ClaudeBinary != "" {
+		b.WriteString(renderWrappedPath("Claude: ", app.HumanizePath(entry.ClaudeBinary), width))
+		b.WriteString("\n")
+	}
+	if strings.TrimSpace(entry.Prompt) != "" {
+		b.WriteString(renderWrappedLines(fmt.Sprintf("Prompt: %s", entry.Prompt), width, len("Prompt: ")))
 		b.WriteString("\n")
-	} else {
-		start, end := m.visibleRange()
-		for i := start; i < end; i++ {
-			selected := i == m.cursor
-			switch m.stage {
-			case stageScheduleList:
-				renderMultilineItem(b, m.items[i], selected, width, 2)
-			case stagePermissionMode:
-				metaWidth := maxMetaWidth(m.items, 18)
-				b.WriteString(renderItemWithMetaWidth(m.items[i], selected, width, metaWidth))
-				b.WriteString("\n")
-			default:
-				b.WriteString(renderItem(m.items[i], selected, width))
-				b.WriteString("\n")
-			}
-		}
 	}
 
-	if m.stage == stageMain && !m.claudeReady {
+	from := now
+	if entry.PausedUntil.After(now) {
+		from = entry.PausedUntil
+	}
+	runs := scheduler.NextRuns(entry, from, scheduleDetailRuns)
+	if entry.Schedule.Type == "login" {
+		b.WriteString(renderLine("Next run: at your next login", width))
 		b.WriteString("\n")
-		b.WriteString(renderLineColored("claude not found in PATH.", width, colorRed))
+	} else if len(runs) == 0 {
+		b.WriteString(renderLine("Next runs: none", width))
 		b.WriteString("\n")
-		if strings.TrimSpace(m.installCmd) != "" {
-			b.WriteString(renderLine(fmt.Sprintf("install: %s", m.installCmd), width))
+	} else {
+		b.WriteString(renderLine("Next runs:", width))
+		b.WriteString("\n")
+		for _, run := range runs {
+			label := scheduler.FormatInZone(entry, run, "Mon Jan 02 2006 15:04")
+			if rel := scheduler.RelativeLabel(run, now); rel != "" {
+				label = fmt.Sprintf("%s (%s)", label, rel)
+			}
+			b.WriteString(renderLine(fmt.Sprintf("  %s", label), width))
 			b.WriteString("\n")
 		}
 	}
-
-	b.WriteString("\n")
-	if m.stage == stagePermissionMode {
-		m.renderPermissionHelp(b, width)
+	if m.inputError != "" {
+		b.WriteString(renderLine(fmt.Sprintf("Error: %s", m.inputError), width))
+		b.WriteString("\n")
 	}
+	b.WriteString("\n")
 	b.WriteString(m.footerHint())
 	b.WriteString("\n")
 }
 
-func (m model) footerHint() string {
-	switch m.stage {
-	case stageMain:
-		return "enter select | q quit"
-	case stageScheduleList:
-		return "enter edit | d delete | esc back | q quit"
+func (m model) renderNextRun(b *strings.Builder, width int) {
+	entry, ok := m.findSchedule(m.nextRunID)
+	if !ok {
+		b.WriteString(renderLine("Schedule not found.", width))
+		b.WriteString("\n")
+		b.WriteString(m.footerHint())
+		b.WriteString("\n")
+		return
+	}
+
+	b.WriteString(renderLine(fmt.Sprintf("Schedule: %s", scheduler.ScheduleLabel(entry)), width))
+	b.WriteString("\n")
+	now := time.Now()
+	if next, err := scheduler.NextRun(entry, now); err == nil {
+		label := formatDetailTime(next, now)
+		if hasClockTime(entry) {
+			label = scheduler.FormatInZone(entry, next, detailTimeLayout(next, now))
+		}
+		if rel := scheduler.RelativeLabel(next, now); rel != "" {
+			label = fmt.Sprintf("%s (%s)", label, rel)
+		}
+		b.WriteString(renderLine(fmt.Sprintf("Next run: %s", label), width))
+		b.WriteString("\n")
+	}
+	if entry.Schedule.Type == "once" {
+		b.WriteString(renderLine("Next run (YYYY-MM-DD HH:MM):", width))
+	} else {
+		b.WriteString(renderLine("Times (24-hour HH:MM, comma-separated):", width))
+	}
+	b.WriteString("\n")
+	b.WriteString(m.nextInput.View())
+	b.WriteString(clearLine)
+	b.WriteString("\n")
+	if m.inputError != "" {
+		b.WriteString(renderLine(fmt.Sprintf("Error: %s", m.inputError), width))
+		b.WriteString("\n")
+	}
+	b.WriteString(m.footerHint())
+	b.WriteString("\n")
+}
+
+func (m model) renderCustomModel(b *strings.Builder, width int) {
+	m.renderContextHeader(b, width)
+	b.WriteString(renderLine("Model name (passed to claude --model as-is):", width))
+	b.WriteString("\n")
+	b.WriteString(m.modelInput.View())
+	b.WriteString(clearLine)
+	b.WriteString("\n")
+	if m.inputError != "" {
+		b.WriteString(renderLine(fmt.Sprintf("Error: %s", m.inputError), width))
+		b.WriteString("\n")
+	}
+	b.WriteString("enter continue | esc back | ctrl+c quit\n")
+}
+
+func (m model) renderProjectPath(b *strings.Builder, width int) {
+	if len(m.projects) == 0 {
+		notice := "Claude has no projects yet; enter the directory to run in."
+		if m.projectsErr != nil && !errors.Is(m.projectsErr, app.ErrNoProjects) {
+			notice = m.projectsErr.Error()
+		}
+		b.WriteString(renderWrappedLines(fmt.Sprintf("Notice: %s", notice), width, len("Notice: ")))
+		b.WriteString("\n")
+	}
+	b.WriteString(renderLine("Project directory (runs there will start new sessions):", width))
+	b.WriteString("\n")
+	b.WriteString(m.pathInput.View())
+	b.WriteString(clearLine)
+	b.WriteString("\n")
+	if m.inputError != "" {
+		b.WriteString(renderLine(fmt.Sprintf("Error: %s", m.inputError), width))
+		b.WriteString("\n")
+	}
+	b.WriteString("enter continue | esc back | q quit\n")
+}
+
+func (m model) renderStats(b *strings.Builder, width int) {
+	window := statsWindows[m.statsWindow]
+	now := time.Now()
+	stats := computeRunStats(m.logs, window.since(now))
+
+	b.WriteString(renderLine(fmt.Sprintf("Run stats: %s.", window.Label), width))
+	b.WriteString("\n")
+	if stats.runs == 0 {
+		b.WriteString(renderLine("No runs in this window.", width))
+		b.WriteString("\n")
+	} else {
+		b.WriteString(renderLine(fmt.Sprintf("Total: %s · %s", runCountLabel(stats.runs), formatRunDuration(stats.duration)), width))
+		b.WriteString("\n")
+		b.WriteString(renderLine(fmt.Sprintf("By status: %s", stats.statusLabel()), width))
+		b.WriteString("\n")
+		b.WriteString("\n")
+		b.WriteString(renderLine("Per schedule:", width))
+		b.WriteString("\n")
+		for _, id := range stats.order {
+			perSchedule := stats.schedules[id]
+			label := id
+			if entry, ok := m.findSchedule(id); ok {
+				label = scheduler.ScheduleLabel(entry)
+				if entry.Description != "" {
+					label = fmt.Sprintf("%s (%s)", entry.Description, label)
+				}
+			} else if perSchedule.preview != "" {
+				label = fmt.Sprintf("%s (deleted)", perSchedule.preview)
+			}
+			line := fmt.Sprintf("  %s · %s · %s", runCountLabel(perSchedule.runs), formatRunDuration(perSchedule.duration), label)
+			b.WriteString(renderLine(line, width))
+			b.WriteString("\n")
+		}
+	}
+	b.WriteString("\n")
+	b.WriteString(renderLine("Based on retained run logs; runs from before durations were recorded count as 0s.", width))
+	b.WriteString("\n")
+	b.WriteString(m.footerHint())
+	b.WriteString("\n")
+}
+
+func (m model) renderList(b *strings.Builder, width int) {
+	switch m.stage {
+	case stageMain:
+		b.WriteString(renderLine("What would you like to do?", width))
+		b.WriteString("\n")
+	case stageProjects:
+		b.WriteString(renderLine("Select a project to continue.", width))
+		b.WriteString("\n")
+	case stageSessions:
+		b.WriteString(renderLine(fmt.Sprintf("Project: %s", m.projectLabel()), width))
+		b.WriteString("\n")
+		if query, ok := m.sessionContentQuery(); ok && query != "" {
+			if _, done := m.contentHits[m.contentKey(query)]; done {
+				b.WriteString(renderLine(fmt.Sprintf("Sessions that mention %q.", query), width))
+			} else {
+				b.WriteString(renderLine("Searching session contents...", width))
+			}
+		} else {
+			b.WriteString(renderLine("Select a session to resume (or start a new one); search with /text to look inside them.", width))
+		}
+		b.WriteString("\n")
+	case stageResumeMode:
+		b.WriteString(renderLine(fmt.Sprintf("Project: %s", m.projectLabel()), width))
+		b.WriteString("\n")
+		b.WriteString(renderLine(fmt.Sprintf("Session: %s", m.sessionLabel()), width))
+		b.WriteString("\n")
+		b.WriteString(renderLine("Continue this session, or fork it into a new one?", width))
+		b.WriteString("\n")
+	case stageModels:
+		b.WriteString(renderLine(fmt.Sprintf("Project: %s", m.projectLabel()), width))
+		b.WriteString("\n")
+		b.WriteString(renderLine(fmt.Sprintf("Session: %s", m.sessionLabel()), width))
+		b.WriteString("\n")
+		b.WriteString(renderLine("Select a Claude model.", width))
+		b.WriteString("\n")
+	case stagePermissionMode:
+		m.renderContextHeader(b, width)
+		b.WriteString(renderLine("Select a permission mode.", width))
+		b.WriteString("\n")
+	case stageNotify:
+		m.renderContextHeader(b, width)
+		b.WriteString(renderLine("When should a run show a notification?", width))
+		b.WriteString("\n")
+	case stageScheduleType:
+		m.renderContextHeader(b, width)
+		b.WriteString(renderLine("Select when to run it.", width))
+		b.WriteString("\n")
+	case stageScheduleWeekday:
+		m.renderContextHeader(b, width)
+		b.WriteString(renderLine("Schedule: Weekly.", width))
+		b.WriteString("\n")
+		b.WriteString(renderLine("Select the day of week.", width))
+		b.WriteString("\n")
+	case stageScheduleDay:
+		m.renderContextHeader(b, width)
+		b.WriteString(renderLine("Schedule: Monthly.", width))
+		b.WriteString("\n")
+		b.WriteString(renderLine("Select the day of month.", width))
+		b.WriteString("\n")
+	case stageSunEvent:
+		m.renderContextHeader(b, width)
+		b.WriteString(renderLine("Schedule: Sunrise / sunset.", width))
+		b.WriteString("\n")
+		b.WriteString(renderLine("Select the event to run at.", width))
+		b.WriteString("\n")
+	case stageScheduleList:
+		b.WriteString(renderLine("Scheduled prompts.", width))
+		b.WriteString("\n")
+	case stageLogs:
+		if m.logSessionsOnly {
+			b.WriteString(renderLine(fmt.Sprintf("Sessions from %s (enter for the resume command).", m.logScheduleLabel()), width))
+		} else if m.logScheduleID != "" {
+			b.WriteString(renderLine(fmt.Sprintf("Run logs for %s.", m.logScheduleLabel()), width))
+		} else {
+			b.WriteString(renderLine("Run logs.", width))
+		}
+		b.WriteString("\n")
+	case stageConfirmDelete:
+		if m.clearingLogs {
+			b.WriteString(renderLine("Clear run logs for every schedule? Their output files are deleted too.", width))
+			b.WriteString("\n")
+		} else if m.pendingDel != nil && m.pendingGroup != "" {
+			members := scheduler.GroupMembers(m.schedules, m.pendingGroup)
+			b.WriteString(renderLine(fmt.Sprintf("Delete every schedule in %s (%d)?", scheduler.GroupLabel(*m.pendingDel), len(members)), width))
+			b.WriteString("\n")
+		} else if m.pendingDel != nil {
+			b.WriteString(renderLine("Delete scheduled prompt?", width))
+			b.WriteString("\n")
+			b.WriteString(renderLine(fmt.Sprintf("%s", scheduler.Preview(m.pendingDel.Prompt, 80)), width))
+			b.WriteString("\n")
+		}
+	case stagePause:
+		if m.pendingPause != nil && m.pendingGroup != "" {
+			members := scheduler.GroupMembers(m.schedules, m.pendingGroup)
+			b.WriteString(renderLine(fmt.Sprintf("Pause every recurring schedule in %s (%d)?", scheduler.GroupLabel(*m.pendingPause), len(members)), width))
+			b.WriteString("\n")
+		} else if m.pendingPause != nil {
+			b.WriteString(renderLine("Pause scheduled prompt?", width))
+			b.WriteString("\n")
+			b.WriteString(renderLine(scheduler.Preview(m.pendingPause.Prompt, 80), width))
+			b.WriteString("\n")
+		}
+	}
+
+	if m.projectsErr != nil && m.stage == stageMain {
+		b.WriteString(renderLine(fmt.Sprintf("Notice: %s", m.projectsErr.Error()), width))
+		b.WriteString("\n")
+	}
+	if m.inputError != "" && (m.stage == stageMain || m.stage == stageLogs || m.stage == stageScheduleList) {
+		b.WriteString(renderLine(fmt.Sprintf("Error: %s", m.inputError), width))
+		b.WriteString("\n")
+	}
+	if m.armedDelete != "" && m.stage == stageScheduleList {
+		if entry, ok := m.findSchedule(m.armedDelete); ok {
+			b.WriteString(renderLineColored(fmt.Sprintf("Press d again to delete %s.", scheduler.ScheduleLabel(entry)), width, colorRed))
+			b.WriteString("\n")
+		}
+	}
+	if m.logNote != "" && m.stage == stageLogs {
+		b.WriteString(renderLine(m.logNote, width))
+		b.WriteString("\n")
+	}
+
+	if m.usesSearch() {
+		b.WriteString(searchLabel)
+		b.WriteString(m.searchInput.View())
+		b.WriteString(clearLine)
+		b.WriteString("\n")
+	}
+	b.WriteString(strings.Repeat("-", max(10, min(width, 60))))
+	b.WriteString("\n")
+
+	if len(m.items) == 0 {
+		empty := "No matches."
+		if m.stage == stageScheduleList {
+			empty = "No active schedules."
+		} else if m.stage == stageLogs && m.logSessionsOnly {
+			empty = "No sessions from this schedule yet."
+		} else if m.stage == stageLogs && m.logScheduleID != "" {
+			empty = "No runs for this schedule yet."
+		} else if m.stage == stageLogs {
+			empty = "No logs yet."
+		}
+		b.WriteString(renderLine(empty, width))
+		b.WriteString("\n")
+	} else {
+		start, end := m.visibleRange()
+		now := time.Now()
+		for i := start; i < end; i++ {
+			selected := i == m.cursor
+			switch m.stage {
+			case stageScheduleList:
+				item := m.items[i]
+				if countdown := countdownLabel(item.next, now); countdown != "" {
+					item.title = fmt.Sprintf("%s · %s", countdown, item.title)
+				}
+				renderMultilineItem(b, item, selected, width, 2)
+			case stageSessions:
+				metaWidth := maxMetaWidth(m.all, 28)
+				b.WriteString(renderItemWithMetaWidth(m.items[i], selected, width, metaWidth))
+				b.WriteString("\n")
+			case stagePermissionMode:
+				metaWidth := maxMetaWidth(m.items, 18)
+				b.WriteString(renderItemWithMetaWidth(m.items[i], selected, width, metaWidth))
+				b.WriteString("\n")
+			default:
+				b.WriteString(renderItem(m.items[i], selected, width))
+				b.WriteString("\n")
+			}
+			if selected {
+				if text := m.expandedLogError(); text != "" {
+					b.WriteString(renderWrappedIndentedLines(text, width, logErrorIndent))
+					b.WriteString("\n")
+				}
+			}
+		}
+	}
+
+	if m.stage == stageMain && !m.claudeReady {
+		b.WriteString("\n")
+		b.WriteString(renderLineColored("claude not found in PATH.", width, colorRed))
+		b.WriteString("\n")
+		if strings.TrimSpace(m.installCmd) != "" {
+			b.WriteString(renderLine(fmt.Sprintf("install: %s", m.installCmd), width))
+			b.WriteString("\n")
+		}
+	}
+
+	b.WriteString("\n")
+	if m.stage == stagePermissionMode {
+		m.renderPermissionHelp(b, width)
+	}
+	if m.stage == stageResumeMode {
+		m.renderResumeModeHelp(b, width)
+	}
+	b.WriteString(m.footerHint())
+	b.WriteString("\n")
+}
+
+func (m model) footerHint() string {
+	switch m.stage {
+	case stageMain:
+		return "enter select | 1-9 jump | q quit"
+	case stageScheduleList:
+		if m.searchInput.Focused() {
+			return "type to filter (#tag @group) | enter edit | esc/tab commands | ctrl+c quit"
+		}
+		if m.quickDelete {
+			return "enter edit | i edit prompt | v details | t set time | w to weekly | y duplicate | l logs | s sessions | p pause | e enable/disable | d d delete | P/D whole group | / search | esc back | q quit"
+		}
+		return "enter edit | i edit prompt | v details | t set time | w to weekly | y duplicate | l logs | s sessions | p pause | e enable/disable | d delete | P/D whole group | / search | esc back | q quit"
 	case stageLogs:
-		return "enter details | r refresh | esc back | q quit"
+		if m.searchInput.Focused() {
+			return "type to filter | enter select | esc/tab commands | ctrl+c quit"
+		}
+		if m.logSessionsOnly {
+			return "enter resume command | c continue session | r refresh | / search | esc back | q quit"
+		}
+		if m.logErrorExpanded {
+			return "enter details | t follow running | c continue session | e hide error | y copy output path | x clear | r refresh | / search | esc back | q quit"
+		}
+		return "enter details | t follow running | c continue session | e full error | y copy output path | x clear | r refresh | / search | esc back | q quit"
 	case stageLogDetail:
+		if entry, ok := m.logDetailEntry(); ok && entry.SessionID != "" {
+			return "c continue session | esc back | q quit"
+		}
 		return "esc back | q quit"
+	case stageScheduleDetail:
+		return "enter edit | t set time | n toggle low priority | l logs | s sessions | esc back | q quit"
+	case stageNextRun:
+		return "enter save | esc back | q quit"
+	case stageStats:
+		return "tab window | esc back | q quit"
 	case stageSetupToken:
 		if m.tokenVerifying {
 			return "q quit"
@@ -665,7 +1517,7 @@ func (m model) footerHint() string {
 			return "enter verify | ctrl+u clear | esc back | q quit"
 		}
 		return "enter verify | ctrl+u clear | esc quit"
-	case stageConfirmDelete:
+	case stageConfirmDelete, stagePause:
 		return "enter confirm | esc back | q quit"
 	default:
 		return "up/down move | enter select | esc back | q quit"
@@ -688,6 +1540,18 @@ func (m model) renderPermissionHelp(b *strings.Builder, width int) {
 	b.WriteString("\n")
 }
 
+func (m model) renderResumeModeHelp(b *strings.Builder, width int) {
+	if len(m.items) == 0 {
+		return
+	}
+	item := m.items[m.cursor]
+	if item.kind != itemResumeMode || item.index < 0 || item.index >= len(resumeModeOptions) {
+		return
+	}
+	b.WriteString(renderLine(fmt.Sprintf("Mode: %s", resumeModeOptions[item.index].Desc), width))
+	b.WriteString("\n")
+}
+
 func (m model) renderContextHeader(b *strings.Builder, width int) {
 	b.WriteString(renderLine(fmt.Sprintf("Project: %s", m.projectLabel()), width))
 	b.WriteString("\n")
@@ -697,28 +1561,62 @@ func (m model) renderContextHeader(b *strings.Builder, width int) {
 		b.WriteString(renderLine(fmt.Sprintf("Model: %s", label), width))
 		b.WriteString("\n")
 	}
-	if preview := m.promptPreview(); preview != "" && m.stage != stagePrompt {
-		b.WriteString(renderLine(fmt.Sprintf("Prompt: %s", preview), width))
+	for _, line := range m.promptHeaderLines(width) {
+		b.WriteString(renderLine(line, width))
 		b.WriteString("\n")
 	}
 }
 
+// On terminals at least this tall the context header wraps the prompt over
+// a few lines instead of cutting it at 80 characters, so it can be checked
+// before it is scheduled.
+const (
+	tallTerminalHeight   = 40
+	promptHeaderMaxLines = 4
+)
+
+func (m model) promptHeaderLines(width int) []string {
+	preview := m.promptPreview()
+	if preview == "" || m.stage == stagePrompt {
+		return nil
+	}
+	if m.height < tallTerminalHeight {
+		return []string{fmt.Sprintf("Prompt: %s", preview)}
+	}
+	const label = "Prompt: "
+	value := strings.Join(strings.Fields(m.currentPrompt()), " ")
+	lines := wrapWithIndent(label+value, width, len(label))
+	if len(lines) > promptHeaderMaxLines {
+		lines = lines[:promptHeaderMaxLines]
+		last := len(lines) - 1
+		lines[last] = truncateToWidth(lines[last]+" ...", width-len(label))
+	}
+	for i := 1; i < len(lines); i++ {
+		lines[i] = strings.Repeat(" ", len(label)) + lines[i]
+	}
+	return lines
+}
+
 func (m model) projectLabel() string {
 	if m.project.DisplayName != "" {
 		return m.project.DisplayName
 	}
 	if m.project.Path != "" {
-		return app.HumanizePath(m.project.Path)
+		return app.DisplayProjectPath(m.project.Path)
 	}
 	return ""
 }
 
 func (m model) sessionLabel() string {
 	if m.selectedSess != nil {
-		if m.selectedSess.Preview != "" {
-			return m.selectedSess.Preview
+		label := m.selectedSess.Preview
+		if label == "" {
+			label = m.selectedSess.ID
+		}
+		if m.selectedFork && m.stage != stageResumeMode {
+			label = fmt.Sprintf("%s (fork)", label)
 		}
-		return m.selectedSess.ID
+		return label
 	}
 	if m.selectedNew {
 		return "Start a new session"
@@ -763,7 +1661,7 @@ func (m *model) setMainItems() {
 			continue
 		}
 		items = append(items, listItem{
-			title:  option.Label,
+			title:  fmt.Sprintf("%d. %s", len(items)+1, option.Label),
 			meta:   option.Meta,
 			filter: strings.ToLower(option.Label + " " + option.Meta),
 			kind:   itemMain,
@@ -777,9 +1675,14 @@ func (m *model) setMainItems() {
 func (m *model) setProjectItems() {
 	m.selectedSess = nil
 	m.selectedNew = false
-	m.selectedModel = app.ModelOption{}
-	m.selectedPerm = "acceptEdits"
+	m.selectedFork = false
+	m.selectedModel = m.defaultModel
+	m.selectedPerm = m.defaultPerm
 	m.promptText = ""
+	m.preCommandText = ""
+	m.postCommandText = ""
+	m.envText = ""
+	m.duplicate = nil
 	m.inputError = ""
 	m.schedule = Schedule{}
 	m.pendingDel = nil
@@ -792,7 +1695,7 @@ func (m *model) setProjectItems() {
 	for i, project := range m.projects {
 		display := project.DisplayName
 		if display == "" {
-			display = app.HumanizePath(project.Path)
+			display = app.DisplayProjectPath(project.Path)
 		}
 		sessionLabel := sessionCountLabel(project.SessionCount)
 		title := fmt.Sprintf("%s (%s)", display, sessionLabel)
@@ -806,6 +1709,13 @@ func (m *model) setProjectItems() {
 			index:  i,
 		})
 	}
+	items = append(items, listItem{
+		title:  "Enter a directory path",
+		meta:   "path",
+		filter: "enter directory path manual new",
+		kind:   itemManualPath,
+		pinned: true,
+	})
 	m.all = items
 	m.applyFilter()
 }
@@ -828,7 +1738,7 @@ func (m *model) setSessionItems() {
 		if title == "" {
 			continue
 		}
-		meta := session.RelTime
+		meta := sessionMeta(session)
 		filter := strings.ToLower(strings.Join([]string{title, session.ID}, " "))
 		items = append(items, listItem{
 			title:  title,
@@ -843,12 +1753,46 @@ func (m *model) setSessionItems() {
 	m.applyFilter()
 }
 
-func (m *model) setModelItems() {
+// sessionMeta helps tell a throwaway session from a long one at a glance.
+func sessionMeta(session app.Session) string {
+	parts := []string{session.RelTime}
+	if session.Lines > 0 {
+		parts = append(parts, fmt.Sprintf("%d msgs", session.Lines))
+	}
+	if session.Size > 0 {
+		parts = append(parts, app.FormatSize(session.Size))
+	}
+	return strings.Join(parts, " · ")
+}
+
+func (m *model) setResumeModeItems() {
 	m.inputError = ""
 	m.searchInput.SetValue("")
-	m.searchInput.Focus()
-	items := make([]listItem, 0, len(m.models))
-	for i, option := range m.models {
+	m.searchInput.Blur()
+	items := make([]listItem, 0, len(resumeModeOptions))
+	for i, option := range resumeModeOptions {
+		items = append(items, listItem{
+			title:  option.Label,
+			meta:   option.Value,
+			filter: strings.ToLower(option.Label + " " + option.Value),
+			kind:   itemResumeMode,
+			index:  i,
+		})
+	}
+	m.all = items
+	m.applyFilter()
+	if m.selectedFork {
+		m.cursor = 1
+		m.ensureCursorVisible()
+	}
+}
+
+func (m *model) setModelItems() {
+	m.inputError = ""
+	m.searchInput.SetValue("")
+	m.searchInput.Focus()
+	items := make([]listItem, 0, len(m.models))
+	for i, option := range m.models {
 		title := option.Label
 		meta := option.Value
 		filter := strings.ToLower(strings.Join([]string{option.Label, option.Value}, " "))
@@ -860,11 +1804,36 @@ func (m *model) setModelItems() {
 			index:  i,
 		})
 	}
+	custom := listItem{
+		title:  "Custom...",
+		meta:   "type a model name",
+		filter: "custom model id",
+		kind:   itemCustomModel,
+		pinned: true,
+	}
+	if m.isCustomModel() {
+		custom.meta = m.selectedModel.Value
+	}
+	items = append(items, custom)
 	m.all = items
 	m.applyFilter()
 	m.selectModelCursor()
 }
 
+// isCustomModel reports whether the selected model was typed in rather than
+// picked from the list.
+func (m model) isCustomModel() bool {
+	if m.selectedModel.Value == "" {
+		return false
+	}
+	for _, option := range m.models {
+		if option.Value == m.selectedModel.Value {
+			return false
+		}
+	}
+	return true
+}
+
 func (m *model) setPermissionModeItems() {
 	m.inputError = ""
 	m.searchInput.SetValue("")
@@ -884,6 +1853,30 @@ func (m *model) setPermissionModeItems() {
 	m.selectPermissionCursor()
 }
 
+func (m *model) setNotifyItems() {
+	m.inputError = ""
+	m.searchInput.SetValue("")
+	items := make([]listItem, 0, len(notifyOptions))
+	for i, option := range notifyOptions {
+		items = append(items, listItem{
+			title:  option.Label,
+			meta:   option.Meta,
+			filter: strings.ToLower(option.Label + " " + option.Meta),
+			kind:   itemNotify,
+			index:  i,
+		})
+	}
+	m.all = items
+	m.applyFilter()
+	for i, item := range m.items {
+		if item.index >= 0 && item.index < len(notifyOptions) && notifyOptions[item.index].Value == m.selectedNote {
+			m.cursor = i
+			m.ensureCursorVisible()
+			return
+		}
+	}
+}
+
 func (m *model) setScheduleTypeItems() {
 	m.inputError = ""
 	m.searchInput.SetValue("")
@@ -924,37 +1917,135 @@ func (m *model) setWeekdayItems() {
 	m.selectWeekdayCursor()
 }
 
+func (m *model) setMonthDayItems() {
+	m.inputError = ""
+	m.searchInput.SetValue("")
+	m.searchInput.Focus()
+	items := make([]listItem, 0, 32)
+	for day := 1; day <= 31; day++ {
+		meta := ""
+		if day > 28 {
+			meta = "last day in shorter months"
+		}
+		items = append(items, listItem{
+			title:  scheduler.OrdinalDay(day),
+			meta:   meta,
+			filter: fmt.Sprintf("%d %s", day, scheduler.OrdinalDay(day)),
+			kind:   itemMonthDay,
+			index:  day,
+		})
+	}
+	items = append(items, listItem{
+		title:  "Last day",
+		meta:   "28th-31st, whichever ends the month",
+		filter: "last day end of month",
+		kind:   itemMonthDay,
+		index:  scheduler.LastDayOfMonth,
+	})
+	m.all = items
+	m.applyFilter()
+	for i, item := range m.items {
+		if item.index == m.schedule.Day {
+			m.cursor = i
+			m.ensureCursorVisible()
+			return
+		}
+	}
+}
+
+func (m *model) setSunEventItems() {
+	m.inputError = ""
+	m.searchInput.SetValue("")
+	m.searchInput.Focus()
+	items := make([]listItem, 0, len(sunEventOptions))
+	for i, option := range sunEventOptions {
+		items = append(items, listItem{
+			title:  option.Label,
+			meta:   option.Meta,
+			filter: strings.ToLower(option.Label + " " + option.Meta),
+			kind:   itemSunEvent,
+			index:  i,
+		})
+	}
+	m.all = items
+	m.applyFilter()
+	for i, item := range m.items {
+		if item.index >= 0 && item.index < len(sunEventOptions) && sunEventOptions[item.index].Value == m.schedule.Event {
+			m.cursor = i
+			m.ensureCursorVisible()
+			return
+		}
+	}
+}
+
 func (m *model) setScheduleItems() {
 	m.inputError = ""
 	m.searchInput.SetValue("")
 	m.searchInput.Focus()
 	items := make([]listItem, 0, len(m.schedules))
 	now := time.Now()
+	lastRuns := make(map[string]scheduler.LogEntry)
+	for _, entry := range m.logs {
+		if last, ok := lastRuns[entry.ScheduleID]; !ok || entry.RanAt.After(last.RanAt) {
+			lastRuns[entry.ScheduleID] = entry
+		}
+	}
 	for i, entry := range m.schedules {
-		if _, ok := nextRunForList(entry, now); !ok {
+		nextRun, upcoming := nextRunForList(entry, now)
+		if entry.Disabled {
+			nextRun = time.Time{}
+		}
+		if !upcoming && entry.Schedule.Type != "once" {
 			continue
 		}
 		preview := scheduler.Preview(entry.Prompt, 200)
 		if preview == "" {
 			preview = "(no prompt)"
 		}
-		scheduleLabel := formatScheduleLabel(entry)
+		scheduleLabel := scheduler.ScheduleLabel(entry)
+		if hasClockTime(entry) {
+			scheduleLabel += scheduler.ZoneSuffix(entry, now)
+		}
 		addedLabel := formatAdded(entry.CreatedAt, now)
-		project := app.HumanizePath(entry.ProjectPath)
+		project := app.DisplayProjectPath(entry.ProjectPath)
 		if project == "" {
 			project = "(no path)"
 		}
-		title := fmt.Sprintf("%s · %s", addedLabel, scheduleLabel)
+		lastRun, ran := lastRuns[entry.ID]
+		title := fmt.Sprintf("%s · %s · %s", addedLabel, scheduleLabel, lastRunLabel(lastRun, ran, now))
+		if !upcoming {
+			title = fmt.Sprintf("(expired) %s", title)
+		}
+		if entry.Disabled {
+			title = fmt.Sprintf("(disabled) %s", title)
+		}
 		if project != "" {
 			title = fmt.Sprintf("%s · %s", title, project)
 		}
-		filter := strings.ToLower(strings.Join([]string{preview, scheduleLabel, project, entry.ID}, " "))
+		if entry.PausedUntil.After(now) {
+			title = fmt.Sprintf("%s · paused until %s", title, formatDetailTime(entry.PausedUntil, now))
+		}
+		if entry.GroupID != "" {
+			title = fmt.Sprintf("%s · @%s", title, entry.GroupID)
+		}
+		tags := scheduler.FormatTags(entry.Tags)
+		if tags != "" {
+			title = fmt.Sprintf("%s · %s", title, tags)
+		}
+		detail := preview
+		if entry.Description != "" {
+			detail = fmt.Sprintf("%s · %s", entry.Description, preview)
+		}
+		filter := strings.ToLower(strings.Join([]string{entry.Description, preview, scheduleLabel, project, entry.ID, tags, entry.GroupName}, " "))
 		items = append(items, listItem{
 			title:  title,
-			detail: preview,
+			tags:   entry.Tags,
+			group:  entry.GroupID,
+			detail: detail,
 			filter: filter,
 			kind:   itemSchedule,
 			index:  i,
+			next:   nextRun,
 		})
 	}
 	m.all = items
@@ -964,20 +2055,24 @@ func (m *model) setScheduleItems() {
 func (m *model) setLogItems() {
 	m.inputError = ""
 	m.searchInput.SetValue("")
-	m.searchInput.Focus()
 	items := make([]listItem, 0, len(m.logs))
 	now := time.Now()
+	if m.logSessionsOnly {
+		m.all = m.sessionItems(now)
+		m.applyFilter()
+		return
+	}
 	for i, entry := range m.logs {
+		if m.logScheduleID != "" && entry.ScheduleID != m.logScheduleID {
+			continue
+		}
 		preview := entry.PromptPreview
 		if preview == "" {
 			preview = "(no prompt)"
 		}
 		runMsg := formatRunMessage(entry)
 		when := scheduler.RelativeLabel(entry.RanAt, now)
-		project := entry.ProjectPath
-		if project == "" {
-			project = m.logProjectPath(entry)
-		}
+		project := app.DisplayProjectPath(m.logProjectPath(entry))
 		title := preview
 		if runMsg != "" {
 			title = fmt.Sprintf("%s · %s", runMsg, preview)
@@ -995,13 +2090,143 @@ func (m *model) setLogItems() {
 	m.applyFilter()
 }
 
+func (m *model) sessionItems(now time.Time) []listItem {
+	runs := make(map[string]int)
+	for _, entry := range m.logs {
+		if entry.ScheduleID == m.logScheduleID && entry.SessionID != "" {
+			runs[entry.SessionID]++
+		}
+	}
+	items := make([]listItem, 0, len(runs))
+	seen := make(map[string]bool)
+	for i, entry := range m.logs {
+		if entry.ScheduleID != m.logScheduleID || entry.SessionID == "" || seen[entry.SessionID] {
+			continue
+		}
+		seen[entry.SessionID] = true
+		preview := entry.ResultSummary
+		if preview == "" {
+			preview = entry.PromptPreview
+		}
+		if preview == "" {
+			preview = "(no prompt)"
+		}
+		title := fmt.Sprintf("%s · %s", shortID(entry.SessionID), preview)
+		if count := runs[entry.SessionID]; count > 1 {
+			title = fmt.Sprintf("%s · %d runs", title, count)
+		}
+		items = append(items, listItem{
+			meta:   scheduler.RelativeLabel(entry.RanAt, now),
+			title:  title,
+			filter: strings.ToLower(strings.Join([]string{preview, entry.SessionID}, " ")),
+			kind:   itemLog,
+			index:  i,
+		})
+	}
+	return items
+}
+
+func shortID(id string) string {
+	if len(id) > 8 {
+		return id[:8]
+	}
+	return id
+}
+
+type logNoteClearMsg struct {
+	seq int
+}
+
+func (m *model) copyOutputPath() tea.Cmd {
+	if len(m.items) == 0 {
+		return nil
+	}
+	item := m.items[m.cursor]
+	if item.kind != itemLog || item.index < 0 || item.index >= len(m.logs) {
+		return nil
+	}
+	path := strings.TrimSpace(m.logs[item.index].OutputPath)
+	if path == "" {
+		m.inputError = "This run has no output file."
+		return nil
+	}
+	if err := copyToClipboard(path); err != nil {
+		m.inputError = err.Error()
+		return nil
+	}
+	m.inputError = ""
+	return m.showLogNote(fmt.Sprintf("Copied path: %s", app.HumanizePath(path)))
+}
+
+func (m *model) showLogNote(note string) tea.Cmd {
+	m.logNote = note
+	m.logNoteSeq++
+	seq := m.logNoteSeq
+	return tea.Tick(2*time.Second, func(time.Time) tea.Msg {
+		return logNoteClearMsg{seq: seq}
+	})
+}
+
+func (m *model) beginClearLogs() {
+	m.clearingLogs = true
+	m.stage = stageConfirmDelete
+	m.inputError = ""
+	m.resetCursor()
+	m.searchInput.SetValue("")
+	m.searchInput.Blur()
+	m.setConfirmDeleteItems()
+}
+
+// clearLogs runs here rather than as an Action: the logs are the user's own,
+// so no sudo is needed.
+func (m *model) clearLogs(index int) tea.Cmd {
+	m.clearingLogs = false
+	m.stage = stageLogs
+	m.resetCursor()
+	m.searchInput.Focus()
+	filter := "all"
+	switch index {
+	case 1:
+		m.refreshLogs()
+		return nil
+	case 2:
+		filter = "failed"
+	}
+	store, err := scheduler.DefaultStore()
+	if err != nil {
+		m.refreshLogs()
+		m.inputError = err.Error()
+		return nil
+	}
+	removed, err := store.ClearLogs(filter)
+	m.refreshLogs()
+	if err != nil {
+		m.inputError = fmt.Sprintf("Clear logs: %v", err)
+		return nil
+	}
+	if removed == 1 {
+		return m.showLogNote("Cleared 1 run.")
+	}
+	return m.showLogNote(fmt.Sprintf("Cleared %d runs.", removed))
+}
+
+func copyToClipboard(text string) error {
+	cmd := exec.Command("pbcopy")
+	cmd.Stdin = strings.NewReader(text)
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("copy to clipboard: %w", err)
+	}
+	return nil
+}
+
 func (m *model) refreshLogs() {
 	store, err := scheduler.DefaultStore()
 	if err != nil {
 		m.inputError = err.Error()
 		return
 	}
-	logs, err := store.LoadLogs(scheduler.MaxRunLogs)
+	runMax, _, _ := scheduler.LogRetention()
+	logs, err := store.LoadLogs(runMax)
 	if err != nil {
 		m.inputError = err.Error()
 		return
@@ -1016,9 +2241,45 @@ func (m *model) refreshLogs() {
 	}
 }
 
+func (m *model) setPauseItems() {
+	items := make([]listItem, 0, len(pauseOptions)+1)
+	now := time.Now()
+	for i, option := range pauseOptions {
+		items = append(items, listItem{
+			title:  option.Label,
+			meta:   formatDetailTime(option.until(now), now),
+			filter: strings.ToLower(option.Label),
+			kind:   itemPause,
+			index:  i,
+		})
+	}
+	paused := m.pendingPause != nil && m.pendingPause.PausedUntil.After(now)
+	for _, entry := range scheduler.GroupMembers(m.schedules, m.pendingGroup) {
+		paused = paused || entry.PausedUntil.After(now)
+	}
+	if paused {
+		items = append(items, listItem{title: "Resume now", meta: "resume", filter: "resume", kind: itemPause, index: -1})
+	}
+	m.all = items
+	m.applyFilter()
+}
+
 func (m *model) setConfirmDeleteItems() {
+	if m.clearingLogs {
+		m.all = []listItem{
+			{title: "Clear failed runs", meta: "failed", filter: "failed", kind: itemConfirm, index: 2},
+			{title: "Clear all logs", meta: "all", filter: "all", kind: itemConfirm, index: 0},
+			{title: "Cancel", meta: "cancel", filter: "cancel", kind: itemConfirm, index: 1},
+		}
+		m.applyFilter()
+		return
+	}
+	title := "Delete this schedule"
+	if m.pendingGroup != "" {
+		title = "Delete the whole group"
+	}
 	items := []listItem{
-		{title: "Delete this schedule", meta: "delete", filter: "delete", kind: itemConfirm, index: 0},
+		{title: title, meta: "delete", filter: "delete", kind: itemConfirm, index: 0},
 		{title: "Cancel", meta: "cancel", filter: "cancel", kind: itemConfirm, index: 1},
 	}
 	m.all = items
@@ -1065,7 +2326,13 @@ func (m *model) selectModelCursor() {
 	if m.selectedModel.Value == "" {
 		return
 	}
+	custom := m.isCustomModel()
 	for i, item := range m.items {
+		if custom && item.kind == itemCustomModel {
+			m.cursor = i
+			m.ensureCursorVisible()
+			return
+		}
 		if item.kind != itemModel {
 			continue
 		}
@@ -1100,8 +2367,9 @@ func (m *model) selectPermissionCursor() {
 func (m *model) handleBack() (tea.Model, tea.Cmd) {
 	if m.editID != "" {
 		switch m.stage {
-		case stagePrompt, stageModels, stagePermissionMode, stageSessions, stageProjects:
+		case stagePrompt, stageModels, stagePermissionMode, stageSessions, stageResumeMode, stageProjects:
 			m.editID = ""
+			m.editOnly = stageMain
 			m.stage = stageScheduleList
 			m.pendingDel = nil
 			m.setScheduleItems()
@@ -1118,10 +2386,30 @@ func (m *model) handleBack() (tea.Model, tea.Cmd) {
 		m.sessions = nil
 		m.selectedSess = nil
 		m.selectedNew = false
-		m.selectedModel = app.ModelOption{}
+		m.selectedFork = false
+		m.selectedModel = m.defaultModel
 		m.setProjectItems()
 		return m, nil
+	case stageResumeMode:
+		m.stage = stageSessions
+		m.resetCursor()
+		m.setSessionItems()
+		return m, nil
 	case stageModels:
+		m.startEnvStage()
+		return m, nil
+	case stageEnv:
+		m.envText = m.envInput.Value()
+		m.envInput.Blur()
+		m.startHookStage(stagePostCommand)
+		return m, nil
+	case stagePostCommand:
+		m.postCommandText = strings.TrimSpace(m.hookInput.Value())
+		m.startHookStage(stagePreCommand)
+		return m, nil
+	case stagePreCommand:
+		m.preCommandText = strings.TrimSpace(m.hookInput.Value())
+		m.hookInput.Blur()
 		m.stage = stagePrompt
 		m.promptInput.SetValue(m.promptText)
 		m.promptInput.Focus()
@@ -1132,20 +2420,67 @@ func (m *model) handleBack() (tea.Model, tea.Cmd) {
 		m.setModelItems()
 		return m, nil
 	case stagePrompt:
-		m.stage = stageSessions
 		m.promptText = strings.TrimSpace(m.promptInput.Value())
 		m.promptInput.Blur()
+		if m.manualProject {
+			m.startProjectPathStage()
+			return m, nil
+		}
+		if m.selectedSess != nil {
+			m.startResumeModeStage()
+			return m, nil
+		}
+		m.stage = stageSessions
 		m.setSessionItems()
 		return m, nil
-	case stageScheduleType:
+	case stageDescription:
+		m.descriptionText = strings.TrimSpace(m.descInput.Value())
+		m.descInput.Blur()
 		m.startPermissionModeStage()
 		return m, nil
+	case stageTags:
+		m.tagsText = strings.TrimSpace(m.tagsInput.Value())
+		m.tagsInput.Blur()
+		m.startDescriptionStage()
+		return m, nil
+	case stageNotify:
+		m.startTagsStage()
+		return m, nil
+	case stageScheduleType:
+		m.startNotifyStage()
+		return m, nil
 	case stageScheduleDate:
 		m.startScheduleTypeStage()
 		return m, nil
-	case stageScheduleWeekday:
+	case stageScheduleWeekday, stageScheduleDay, stageSunEvent:
+		if m.converting {
+			m.converting = false
+			m.editID = ""
+			m.startScheduleListStage()
+			return m, nil
+		}
 		m.startScheduleTypeStage()
 		return m, nil
+	case stageSunLocation:
+		m.startSunEventStage()
+		return m, nil
+	case stageScheduleCron, stageScheduleInterval:
+		m.startScheduleTypeStage()
+		return m, nil
+	case stageStartDate:
+		switch m.schedule.Type {
+		case "cron":
+			m.startScheduleCronStage()
+		case "interval":
+			m.startScheduleIntervalStage()
+		case "sun":
+			m.startSunLocationStage()
+		case "login":
+			m.startScheduleTypeStage()
+		default:
+			m.startScheduleTimeStage()
+		}
+		return m, nil
 	case stageScheduleTime:
 		if m.schedule.Type == "once" {
 			m.startScheduleDateStage()
@@ -1155,9 +2490,26 @@ func (m *model) handleBack() (tea.Model, tea.Cmd) {
 			m.startScheduleWeekdayStage()
 			return m, nil
 		}
+		if m.schedule.Type == "monthly" {
+			m.startScheduleDayStage()
+			return m, nil
+		}
 		m.startScheduleTypeStage()
 		return m, nil
-	case stageScheduleList, stageLogs:
+	case stageLogs:
+		if m.logScheduleID != "" {
+			m.logScheduleID = ""
+			m.startScheduleListStage()
+			return m, nil
+		}
+		m.startMainStage()
+		return m, nil
+	case stageLogTail:
+		// Stops the tick, which checks the stage.
+		m.stage = stageLogs
+		m.refreshLogs()
+		return m, nil
+	case stageScheduleList:
 		m.startMainStage()
 		return m, nil
 	case stageSetupToken:
@@ -1171,10 +2523,51 @@ func (m *model) handleBack() (tea.Model, tea.Cmd) {
 		m.stage = stageLogs
 		return m, nil
 	case stageConfirmDelete:
+		if m.clearingLogs {
+			m.clearingLogs = false
+			m.stage = stageLogs
+			m.searchInput.Focus()
+			m.refreshLogs()
+			return m, nil
+		}
 		m.stage = stageScheduleList
 		m.pendingDel = nil
 		m.setScheduleItems()
 		return m, nil
+	case stageScheduleDetail:
+		m.detailScheduleID = ""
+		m.stage = stageScheduleList
+		m.searchInput.Focus()
+		return m, nil
+	case stagePause:
+		m.stage = stageScheduleList
+		m.pendingPause = nil
+		m.setScheduleItems()
+		return m, nil
+	case stageNextRun:
+		m.nextRunID = ""
+		m.nextInput.Blur()
+		m.startScheduleListStage()
+		return m, nil
+	case stageConfirmEdit:
+		m.returnFromConfirmEdit()
+		return m, nil
+	case stageCustomModel:
+		m.modelInput.Blur()
+		m.startModelStage()
+		return m, nil
+	case stageStats:
+		m.startMainStage()
+		return m, nil
+	case stageProjectPath:
+		m.pathInput.Blur()
+		m.manualProject = false
+		if len(m.projects) == 0 {
+			m.startMainStage()
+			return m, nil
+		}
+		m.startProjectStage()
+		return m, nil
 	case stageMain:
 		m.err = ErrUserQuit
 		return m, tea.Quit
@@ -1187,8 +2580,38 @@ func (m *model) handleBack() (tea.Model, tea.Cmd) {
 func (m *model) updateList(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
+		if m.armedDelete != "" && msg.String() != "d" {
+			m.armedDelete = ""
+		}
 		allowJK := !(m.usesSearch() && m.searchInput.Focused())
+		if m.listSearching() && msg.Type == tea.KeyRunes {
+			// Typed text is the filter, never a hotkey.
+			break
+		}
 		switch msg.String() {
+		case "tab":
+			if m.hasListCommands() {
+				if m.searchInput.Focused() {
+					m.searchInput.Blur()
+				} else {
+					m.searchInput.Focus()
+				}
+				return m, nil
+			}
+		case "/":
+			if m.hasListCommands() {
+				m.searchInput.Focus()
+				return m, nil
+			}
+		case "#", "@":
+			// Start a tag or group filter straight from the hotkeys.
+			if m.stage == stageScheduleList {
+				m.searchInput.Focus()
+				m.searchInput.SetValue(strings.TrimSpace(m.searchInput.Value()+" ") + msg.String())
+				m.searchInput.CursorEnd()
+				m.applyFilter()
+				return m, nil
+			}
 		case "enter":
 			return m, m.selectCurrent()
 		case "up":
@@ -1223,14 +2646,148 @@ func (m *model) updateList(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.ensureCursorVisible()
 			return m, nil
 		case "d":
+			if m.stage == stageScheduleList && m.quickDelete {
+				return m, m.armDelete()
+			}
+			if m.stage == stageScheduleList {
+				return m, m.beginDelete(false)
+			}
+		case "p":
 			if m.stage == stageScheduleList {
-				return m, m.beginDelete()
+				m.beginPause(false)
+				return m, nil
+			}
+		case "P":
+			if m.stage == stageScheduleList {
+				m.beginPause(true)
+				return m, nil
+			}
+		case "D":
+			if m.stage == stageScheduleList {
+				return m, m.beginDelete(true)
+			}
+		case "v":
+			if m.stage == stageScheduleList && len(m.items) > 0 {
+				item := m.items[m.cursor]
+				if item.kind == itemSchedule && item.index >= 0 && item.index < len(m.schedules) {
+					m.detailScheduleID = m.schedules[item.index].ID
+					m.stage = stageScheduleDetail
+					m.inputError = ""
+					m.searchInput.Blur()
+					return m, nil
+				}
+			}
+		case "1", "2", "3", "4", "5", "6", "7", "8", "9":
+			if m.stage == stageMain {
+				index := int(msg.String()[0] - '1')
+				if index < len(m.items) {
+					m.cursor = index
+					return m, m.selectCurrent()
+				}
+				return m, nil
+			}
+		case "ctrl+e":
+			if m.stage == stageScheduleList && len(m.items) > 0 {
+				item := m.items[m.cursor]
+				if item.kind == itemSchedule && item.index >= 0 && item.index < len(m.schedules) {
+					return m, m.editScheduleJSON(m.schedules[item.index])
+				}
+			}
+		case "i":
+			if m.stage == stageScheduleList && len(m.items) > 0 {
+				item := m.items[m.cursor]
+				if item.kind == itemSchedule && item.index >= 0 && item.index < len(m.schedules) {
+					m.startEditStage(m.schedules[item.index], stagePrompt)
+					return m, nil
+				}
+			}
+		case "w":
+			if m.stage == stageScheduleList && len(m.items) > 0 {
+				item := m.items[m.cursor]
+				if item.kind == itemSchedule && item.index >= 0 && item.index < len(m.schedules) {
+					m.startWeeklyConversion(m.schedules[item.index])
+					return m, nil
+				}
+			}
+		case "e":
+			if m.stage == stageLogs {
+				m.logErrorExpanded = !m.logErrorExpanded
+				m.ensureCursorVisible()
+				return m, nil
+			}
+			if m.stage == stageScheduleList && len(m.items) > 0 {
+				item := m.items[m.cursor]
+				if item.kind == itemSchedule && item.index >= 0 && item.index < len(m.schedules) {
+					return m, m.toggleDisabled(m.schedules[item.index])
+				}
+			}
+		case "x":
+			if m.stage == stageLogs && !m.logSessionsOnly {
+				m.beginClearLogs()
+				return m, nil
+			}
+		case "t":
+			if m.stage == stageLogs && !m.logSessionsOnly && len(m.items) > 0 {
+				item := m.items[m.cursor]
+				if item.kind == itemLog && item.index >= 0 && item.index < len(m.logs) {
+					if m.logs[item.index].Status != "running" {
+						m.inputError = "This run isn't running; enter shows its output."
+						return m, nil
+					}
+					return m, m.startLogTail(m.logs[item.index])
+				}
+			}
+			if m.stage == stageScheduleList && len(m.items) > 0 {
+				item := m.items[m.cursor]
+				if item.kind == itemSchedule && item.index >= 0 && item.index < len(m.schedules) {
+					m.startNextRunStage(m.schedules[item.index])
+					return m, nil
+				}
+			}
+		case "l":
+			if m.stage == stageScheduleList && len(m.items) > 0 {
+				item := m.items[m.cursor]
+				if item.kind == itemSchedule && item.index >= 0 && item.index < len(m.schedules) {
+					m.startLogsStage(m.schedules[item.index].ID)
+					return m, nil
+				}
+			}
+		case "s":
+			if m.stage == stageScheduleList && len(m.items) > 0 {
+				item := m.items[m.cursor]
+				if item.kind == itemSchedule && item.index >= 0 && item.index < len(m.schedules) {
+					m.startSessionsStage(m.schedules[item.index].ID)
+					return m, nil
+				}
 			}
 		case "r":
 			if m.stage == stageLogs {
 				m.refreshLogs()
 				return m, nil
 			}
+		case "y":
+			if m.stage == stageLogs && !m.logSessionsOnly {
+				return m, m.copyOutputPath()
+			}
+			if m.stage == stageScheduleList && len(m.items) > 0 {
+				item := m.items[m.cursor]
+				if item.kind == itemSchedule && item.index >= 0 && item.index < len(m.schedules) {
+					m.startDuplicateFlow(m.schedules[item.index])
+					return m, nil
+				}
+			}
+		case "c":
+			if m.stage == stageLogs && len(m.items) > 0 {
+				item := m.items[m.cursor]
+				if item.kind == itemLog && item.index >= 0 && item.index < len(m.logs) {
+					if m.logs[item.index].SessionID == "" {
+						m.inputError = "This run has no session to continue."
+						return m, nil
+					}
+					m.startContinueFromLog(m.logs[item.index])
+					return m, nil
+				}
+			}
 		}
 	}
 
@@ -1242,10 +2799,70 @@ func (m *model) updateList(msg tea.Msg) (tea.Model, tea.Cmd) {
 	m.searchInput, cmd = m.searchInput.Update(msg)
 	if m.searchInput.Value() != prev {
 		m.applyFilter()
+		return m, tea.Batch(cmd, m.contentSearchCmd())
 	}
 	return m, cmd
 }
 
+// contentSearchMsg fires once typing pauses on a /query; contentResultMsg
+// carries the sessions that matched it.
+type contentSearchMsg struct {
+	query string
+}
+
+type contentResultMsg struct {
+	key  string
+	hits map[string]bool
+}
+
+// sessionContentQuery returns the text after a leading "/" in the sessions
+// search, which looks inside the sessions instead of at their previews.
+func (m model) sessionContentQuery() (string, bool) {
+	if m.stage != stageSessions {
+		return "", false
+	}
+	value := strings.TrimSpace(m.searchInput.Value())
+	if !strings.HasPrefix(value, "/") {
+		return "", false
+	}
+	return strings.ToLower(strings.TrimSpace(value[1:])), true
+}
+
+// Results are kept per project and query, so retyping or backspacing to an
+// earlier query doesn't scan again.
+func (m model) contentKey(query string) string {
+	return m.project.Path + "\x00" + query
+}
+
+func (m *model) contentSearchCmd() tea.Cmd {
+	query, ok := m.sessionContentQuery()
+	if !ok || query == "" {
+		return nil
+	}
+	if _, done := m.contentHits[m.contentKey(query)]; done {
+		return nil
+	}
+	return tea.Tick(300*time.Millisecond, func(time.Time) tea.Msg {
+		return contentSearchMsg{query: query}
+	})
+}
+
+func searchSessionsCmd(key, query string, sessions []app.Session) tea.Cmd {
+	paths := make([]string, 0, len(sessions))
+	for _, session := range sessions {
+		paths = append(paths, session.Path)
+	}
+	return func() tea.Msg {
+		hits := make(map[string]bool)
+		for _, path := range paths {
+			if app.SearchSession(path, query) {
+				hits[path] = true
+			}
+		}
+		return contentResultMsg{key: key, hits: hits}
+	}
+}
+
 func (m *model) updateLogDetail(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
@@ -1253,6 +2870,11 @@ func (m *model) updateLogDetail(msg tea.Msg) (tea.Model, tea.Cmd) {
 		case "esc":
 			m.stage = stageLogs
 			return m, nil
+		case "c":
+			if entry, ok := m.logDetailEntry(); ok {
+				m.startContinueFromLog(entry)
+			}
+			return m, nil
 		case "q", "ctrl+c":
 			m.err = ErrUserQuit
 			return m, tea.Quit
@@ -1261,7 +2883,438 @@ func (m *model) updateLogDetail(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return m, nil
 }
 
-func (m *model) beginDelete() tea.Cmd {
+func (m *model) updateScheduleDetail(msg tea.Msg) (tea.Model, tea.Cmd) {
+	key, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return m, nil
+	}
+	entry, found := m.findSchedule(m.detailScheduleID)
+	if !found {
+		return m, nil
+	}
+	switch key.String() {
+	case "enter":
+		m.detailScheduleID = ""
+		m.startEditFlow(entry)
+	case "t":
+		m.startNextRunStage(entry)
+		if m.stage == stageNextRun {
+			m.detailScheduleID = ""
+		}
+	case "n":
+		updated := entry
+		updated.LowPriority = !entry.LowPriority
+		if updated.LowPriority {
+			updated.ProcessType = ""
+		}
+		updated.UpdatedAt = time.Now()
+		m.action = Action{Kind: ActionReplace, Entry: &updated, ScheduleID: entry.ID}
+		return m, tea.Quit
+	case "l":
+		m.detailScheduleID = ""
+		m.startLogsStage(entry.ID)
+	case "s":
+		m.detailScheduleID = ""
+		m.startSessionsStage(entry.ID)
+	}
+	return m, nil
+}
+
+// toggleDisabled switches a schedule off (config kept, launchd job off and
+// wake cancelled) or back on with a fresh next run.
+func (m *model) toggleDisabled(entry scheduler.ScheduleEntry) tea.Cmd {
+	updated := entry
+	now := time.Now()
+	updated.Disabled = !entry.Disabled
+	updated.UpdatedAt = now
+	if !updated.Disabled {
+		next, err := scheduler.NextRun(updated, now)
+		if err != nil {
+			m.inputError = fmt.Sprintf("Can't enable: %v", err)
+			return nil
+		}
+		updated.NextRun = next
+		updated.WakeTime = scheduler.FormatPMSet(next)
+	}
+	m.action = Action{Kind: ActionReplace, Entry: &updated, ScheduleID: entry.ID}
+	return tea.Quit
+}
+
+// startWeeklyConversion turns a daily schedule into a weekly one, asking only
+// for the weekday and keeping its time.
+func (m *model) startWeeklyConversion(entry scheduler.ScheduleEntry) {
+	if entry.Schedule.Type != "daily" {
+		m.inputError = "Only daily schedules can be converted to weekly."
+		return
+	}
+	m.loadEditState(entry)
+	m.converting = true
+	m.schedule.Type = "weekly"
+	m.schedule.Weekday = ""
+	m.startScheduleWeekdayStage()
+}
+
+func (m *model) startNextRunStage(entry scheduler.ScheduleEntry) {
+	if entry.Schedule.Type == "sun" {
+		m.inputError = "Sun schedules follow the sun; edit the event or location instead."
+		return
+	}
+	if entry.Schedule.Type == "cron" {
+		m.inputError = "Cron schedules follow their expression; edit it instead."
+		return
+	}
+	if entry.Schedule.Type == "interval" {
+		m.inputError = "Interval schedules repeat from when they were created; edit the interval instead."
+		return
+	}
+	if entry.Schedule.Type == "login" {
+		m.inputError = "Login schedules run when you log in; there's no time to set."
+		return
+	}
+	m.nextRunID = entry.ID
+	m.stage = stageNextRun
+	m.inputError = ""
+	m.searchInput.Blur()
+	if entry.Schedule.Type == "once" {
+		m.nextInput.Placeholder = "YYYY-MM-DD HH:MM"
+		m.nextInput.SetValue(strings.TrimSpace(entry.Schedule.Date + " " + entry.Schedule.Time))
+	} else {
+		m.nextInput.Placeholder = "HH:MM"
+		m.nextInput.SetValue(strings.Join(scheduler.ScheduleTimes(entry.Schedule), ", "))
+	}
+	m.nextInput.CursorEnd()
+	m.nextInput.Focus()
+}
+
+func (m *model) updateNextRun(msg tea.Msg) (tea.Model, tea.Cmd) {
+	key, ok := msg.(tea.KeyMsg)
+	if !ok || key.Type != tea.KeyEnter {
+		prev := m.nextInput.Value()
+		var cmd tea.Cmd
+		m.nextInput, cmd = m.nextInput.Update(msg)
+		if m.nextInput.Value() != prev {
+			m.inputError = ""
+		}
+		return m, cmd
+	}
+
+	entry, found := m.findSchedule(m.nextRunID)
+	if !found {
+		return m, nil
+	}
+	schedule, err := parseNextRunValue(entry, m.nextInput.Value())
+	if err != nil {
+		m.inputError = err.Error()
+		return m, nil
+	}
+	candidate := entry
+	candidate.Schedule = scheduler.Schedule{
+		Type:    schedule.Type,
+		Date:    schedule.Date,
+		Time:    schedule.Time,
+		Times:   schedule.Times,
+		Weekday: schedule.Weekday,
+		Day:     schedule.Day,
+	}
+	candidate.Timezone = schedule.Timezone
+	if _, err := scheduler.NextRun(candidate, time.Now()); err != nil {
+		m.inputError = err.Error()
+		return m, nil
+	}
+
+	m.nextInput.Blur()
+	m.loadEditState(entry)
+	schedule.StartDate = m.schedule.StartDate
+	m.schedule = schedule
+	return m, m.finishEdit()
+}
+
+func parseNextRunValue(entry scheduler.ScheduleEntry, value string) (Schedule, error) {
+	value = strings.TrimSpace(value)
+	schedule := Schedule{
+		Type:     entry.Schedule.Type,
+		Weekday:  entry.Schedule.Weekday,
+		Day:      entry.Schedule.Day,
+		Timezone: app.ScheduleTimezone(),
+	}
+	if schedule.Type == "once" {
+		fields := strings.Fields(value)
+		if len(fields) != 2 || !isValidDate(fields[0]) || !isValidTime(fields[1]) {
+			return schedule, fmt.Errorf("enter the next run as YYYY-MM-DD HH:MM")
+		}
+		schedule.Date = fields[0]
+		schedule.Time = fields[1]
+		if err := validateOnceSchedule(schedule.Date, schedule.Time, schedule.Timezone); err != nil {
+			return schedule, err
+		}
+		return schedule, nil
+	}
+	times, err := scheduler.ParseTimes(value)
+	if err != nil {
+		return schedule, fmt.Errorf("enter times as HH:MM (24-hour), separated by commas")
+	}
+	if len(times) > 1 && schedule.Type == "monthly" {
+		return schedule, fmt.Errorf("monthly schedules take a single time")
+	}
+	schedule.Time = times[0]
+	if len(times) > 1 {
+		schedule.Times = times
+	}
+	return schedule, nil
+}
+
+// startContinueFromLog starts a new schedule that resumes the session a run
+// created, with the project and model carried over and a fresh prompt.
+func (m *model) startContinueFromLog(entry scheduler.LogEntry) {
+	if entry.SessionID == "" {
+		return
+	}
+	projectPath := m.logProjectPath(entry)
+	if projectPath == "" {
+		m.inputError = "Project for this run is unknown."
+		return
+	}
+	m.project = m.findProject(projectPath)
+	if m.project.Path == "" {
+		m.project = app.Project{Path: projectPath, DisplayName: app.DisplayProjectPath(projectPath)}
+	}
+	if sessions, err := app.ListSessions(m.project.Path); err == nil {
+		m.sessions = sessions
+	}
+	m.selectedSess = nil
+	for i := range m.sessions {
+		if m.sessions[i].ID == entry.SessionID {
+			m.selectedSess = &m.sessions[i]
+			break
+		}
+	}
+	if m.selectedSess == nil {
+		m.selectedSess = &app.Session{ID: entry.SessionID, Preview: entry.SessionID}
+	}
+
+	model := entry.Model
+	if schedule, ok := m.findSchedule(entry.ScheduleID); ok {
+		if model == "" {
+			model = schedule.Model
+		}
+		if schedule.PermissionMode != "" {
+			m.selectedPerm = schedule.PermissionMode
+		}
+	}
+	m.editID = ""
+	m.manualProject = false
+	m.selectedNew = false
+	m.selectedFork = false
+	m.selectedModel = m.findModel(model)
+	m.promptText = ""
+	m.preCommandText = ""
+	m.postCommandText = ""
+	m.envText = ""
+	m.duplicate = nil
+	m.descriptionText = ""
+	m.selectedNote = ""
+	m.tagsText = ""
+	m.schedule = Schedule{}
+	m.startPromptStage()
+}
+
+type editorDoneMsg struct {
+	id   string
+	path string
+	err  error
+}
+
+// editScheduleJSON opens the schedule in $EDITOR; the edit only takes effect
+// if it parses back into a valid entry.
+func (m *model) editScheduleJSON(entry scheduler.ScheduleEntry) tea.Cmd {
+	data, err := json.MarshalIndent(entry, "", "  ")
+	if err != nil {
+		m.inputError = err.Error()
+		return nil
+	}
+	file, err := os.CreateTemp("", fmt.Sprintf("wakeclaude-%s-*.json", entry.ID))
+	if err != nil {
+		m.inputError = err.Error()
+		return nil
+	}
+	_, err = file.Write(append(data, '\n'))
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(file.Name())
+		m.inputError = err.Error()
+		return nil
+	}
+
+	editor := strings.Fields(os.Getenv("VISUAL"))
+	if len(editor) == 0 {
+		editor = strings.Fields(os.Getenv("EDITOR"))
+	}
+	if len(editor) == 0 {
+		editor = []string{"vi"}
+	}
+	path := file.Name()
+	cmd := exec.Command(editor[0], append(editor[1:], path)...)
+	return tea.ExecProcess(cmd, func(err error) tea.Msg {
+		return editorDoneMsg{id: entry.ID, path: path, err: err}
+	})
+}
+
+func (m *model) finishEditJSON(msg editorDoneMsg) {
+	defer os.Remove(msg.path)
+	if msg.err != nil {
+		m.inputError = fmt.Sprintf("editor: %v", msg.err)
+		return
+	}
+	original, ok := m.findSchedule(msg.id)
+	if !ok {
+		m.inputError = "Schedule not found."
+		return
+	}
+	data, err := os.ReadFile(msg.path)
+	if err != nil {
+		m.inputError = err.Error()
+		return
+	}
+	entry, changed, err := parseEditedSchedule(data, original, time.Now())
+	if err != nil {
+		m.inputError = fmt.Sprintf("edit discarded: %v", err)
+		return
+	}
+	if !changed {
+		m.inputError = ""
+		return
+	}
+	m.action = Action{
+		Kind:       ActionReplace,
+		Entry:      &entry,
+		ScheduleID: original.ID,
+	}
+}
+
+func parseEditedSchedule(data []byte, original scheduler.ScheduleEntry, now time.Time) (scheduler.ScheduleEntry, bool, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	var entry scheduler.ScheduleEntry
+	if err := decoder.Decode(&entry); err != nil {
+		return original, false, fmt.Errorf("invalid json: %w", err)
+	}
+	if decoder.More() {
+		return original, false, fmt.Errorf("invalid json: unexpected data after the schedule")
+	}
+
+	// Identity and bookkeeping fields are not editable.
+	entry.ID = original.ID
+	entry.CreatedAt = original.CreatedAt
+	entry.NextRun = original.NextRun
+	entry.WakeTime = original.WakeTime
+	entry.UpdatedAt = original.UpdatedAt
+
+	before, _ := json.Marshal(original)
+	after, _ := json.Marshal(entry)
+	if bytes.Equal(before, after) {
+		return original, false, nil
+	}
+
+	if strings.TrimSpace(entry.Prompt) == "" {
+		return original, false, fmt.Errorf("prompt cannot be empty")
+	}
+	if strings.TrimSpace(entry.ProjectPath) == "" {
+		return original, false, fmt.Errorf("projectPath cannot be empty")
+	}
+	if !scheduler.ValidNotifyMode(entry.Notify) {
+		return original, false, fmt.Errorf("unknown notify setting: %s", entry.Notify)
+	}
+	if entry.Timezone != "" {
+		if _, err := time.LoadLocation(entry.Timezone); err != nil {
+			return original, false, fmt.Errorf("unknown timezone: %s", entry.Timezone)
+		}
+	}
+	next, err := scheduler.NextRun(entry, now)
+	if err != nil {
+		return original, false, err
+	}
+	entry.NextRun = next
+	entry.WakeTime = scheduler.FormatPMSet(next)
+	entry.UpdatedAt = now
+	return entry, true, nil
+}
+
+func (m *model) updateStats(msg tea.Msg) (tea.Model, tea.Cmd) {
+	key, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return m, nil
+	}
+	switch key.String() {
+	case "tab", "right", "l":
+		m.statsWindow = (m.statsWindow + 1) % len(statsWindows)
+	case "shift+tab", "left", "h":
+		m.statsWindow = (m.statsWindow + len(statsWindows) - 1) % len(statsWindows)
+	}
+	return m, nil
+}
+
+func (m *model) beginPause(group bool) {
+	if len(m.items) == 0 {
+		return
+	}
+	item := m.items[m.cursor]
+	if item.kind != itemSchedule || item.index < 0 || item.index >= len(m.schedules) {
+		return
+	}
+	entry := m.schedules[item.index]
+	m.pendingGroup = ""
+	if group {
+		if entry.GroupID == "" {
+			m.inputError = "This schedule isn't in a group."
+			return
+		}
+		m.pendingGroup = entry.GroupID
+	} else if entry.Schedule.Type == "once" {
+		m.inputError = "One-time schedules can't be paused; delete it instead."
+		return
+	}
+	m.pendingPause = &entry
+	m.stage = stagePause
+	m.resetCursor()
+	m.searchInput.SetValue("")
+	m.searchInput.Blur()
+	m.setPauseItems()
+}
+
+// quickDeleteWindow is how long a first d stays armed with quickDelete on.
+const quickDeleteWindow = 3 * time.Second
+
+type disarmDeleteMsg struct {
+	seq int
+}
+
+// armDelete arms the selected schedule on the first d and deletes it on a
+// second d for the same row within quickDeleteWindow.
+func (m *model) armDelete() tea.Cmd {
+	if len(m.items) == 0 {
+		return nil
+	}
+	item := m.items[m.cursor]
+	if item.kind != itemSchedule || item.index < 0 || item.index >= len(m.schedules) {
+		return nil
+	}
+	id := m.schedules[item.index].ID
+	if m.armedDelete == id {
+		m.armedDelete = ""
+		m.action = Action{Kind: ActionDelete, ScheduleID: id}
+		return tea.Quit
+	}
+	m.armedDelete = id
+	m.armedSeq++
+	seq := m.armedSeq
+	return tea.Tick(quickDeleteWindow, func(time.Time) tea.Msg {
+		return disarmDeleteMsg{seq: seq}
+	})
+}
+
+func (m *model) beginDelete(group bool) tea.Cmd {
 	if len(m.items) == 0 {
 		return nil
 	}
@@ -1273,6 +3326,14 @@ func (m *model) beginDelete() tea.Cmd {
 		return nil
 	}
 	entry := m.schedules[item.index]
+	m.pendingGroup = ""
+	if group {
+		if entry.GroupID == "" {
+			m.inputError = "This schedule isn't in a group."
+			return nil
+		}
+		m.pendingGroup = entry.GroupID
+	}
 	m.pendingDel = &entry
 	m.stage = stageConfirmDelete
 	m.resetCursor()
@@ -1292,7 +3353,17 @@ func (m *model) applyInputSizing() {
 	m.promptInput.SetWidth(width)
 	m.promptInput.SetHeight(promptHeight(m.height))
 	m.dateInput.Width = width
+	m.descInput.Width = width
+	m.hookInput.Width = width
+	m.envInput.SetWidth(width)
+	m.envInput.SetHeight(min(promptHeight(m.height), 6))
+	m.tagsInput.Width = width
 	m.timeInput.Width = width
+	m.locInput.Width = width
+	m.cronInput.Width = width
+	m.startInput.Width = width
+	m.everyInput.Width = width
+	m.modelInput.Width = width
 }
 
 func (m *model) updatePrompt(msg tea.Msg) (tea.Model, tea.Cmd) {
@@ -1307,8 +3378,15 @@ func (m *model) updatePrompt(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.inputError = "Prompt cannot be empty."
 			return m, cmd
 		}
+		if app.LooksLikeShellCommand(value) && m.shellWarned != value {
+			m.shellWarned = value
+			return m, cmd
+		}
 		m.promptText = value
-		m.startModelStage()
+		if m.editOnly == stagePrompt {
+			return m, m.finishEdit()
+		}
+		m.startHookStage(stagePreCommand)
 		return m, cmd
 	}
 
@@ -1374,38 +3452,120 @@ func (m *model) updateSetupToken(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, nil
 		}
 	}
-
+
+	var cmd tea.Cmd
+	prev := m.tokenInput.Value()
+	m.tokenInput, cmd = m.tokenInput.Update(msg)
+	if m.tokenInput.Value() != prev {
+		m.inputError = ""
+	}
+	return m, cmd
+}
+
+type tokenVerifyMsg struct {
+	err error
+}
+
+type tokenSpinnerMsg struct{}
+
+type scheduleTickMsg struct{}
+
+// logTailMsg carries the seq of the follow it belongs to, so a stale tick
+// from an earlier one stops instead of doubling up.
+type logTailMsg struct {
+	seq int
+}
+
+func logTailCmd(seq int) tea.Cmd {
+	return tea.Tick(time.Second, func(time.Time) tea.Msg {
+		return logTailMsg{seq: seq}
+	})
+}
+
+func scheduleTickCmd() tea.Cmd {
+	return tea.Tick(30*time.Second, func(time.Time) tea.Msg {
+		return scheduleTickMsg{}
+	})
+}
+
+func verifyTokenCmd(token string) tea.Cmd {
+	return func() tea.Msg {
+		if err := app.VerifyOAuthToken("", token); err != nil {
+			return tokenVerifyMsg{err: err}
+		}
+		if err := app.SaveOAuthToken(token); err != nil {
+			return tokenVerifyMsg{err: err}
+		}
+		return tokenVerifyMsg{}
+	}
+}
+
+func tokenSpinnerCmd() tea.Cmd {
+	return tea.Tick(120*time.Millisecond, func(time.Time) tea.Msg {
+		return tokenSpinnerMsg{}
+	})
+}
+
+func (m *model) updateHookCommand(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if key, ok := msg.(tea.KeyMsg); ok && key.Type == tea.KeyEnter {
+		value := strings.TrimSpace(m.hookInput.Value())
+		if m.stage == stagePreCommand {
+			m.preCommandText = value
+			m.startHookStage(stagePostCommand)
+			return m, nil
+		}
+		m.postCommandText = value
+		m.hookInput.Blur()
+		m.startEnvStage()
+		return m, nil
+	}
+	var cmd tea.Cmd
+	m.hookInput, cmd = m.hookInput.Update(msg)
+	return m, cmd
+}
+
+func (m *model) updateEnv(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if key, ok := msg.(tea.KeyMsg); ok && key.Type == tea.KeyCtrlD {
+		if _, err := scheduler.ParseEnv(m.envInput.Value()); err != nil {
+			m.inputError = err.Error()
+			return m, nil
+		}
+		m.envText = strings.TrimSpace(m.envInput.Value())
+		m.envInput.Blur()
+		m.startModelStage()
+		return m, nil
+	}
 	var cmd tea.Cmd
-	prev := m.tokenInput.Value()
-	m.tokenInput, cmd = m.tokenInput.Update(msg)
-	if m.tokenInput.Value() != prev {
+	prev := m.envInput.Value()
+	m.envInput, cmd = m.envInput.Update(msg)
+	if m.envInput.Value() != prev {
 		m.inputError = ""
 	}
 	return m, cmd
 }
 
-type tokenVerifyMsg struct {
-	err error
-}
-
-type tokenSpinnerMsg struct{}
-
-func verifyTokenCmd(token string) tea.Cmd {
-	return func() tea.Msg {
-		if err := app.VerifyOAuthToken(token); err != nil {
-			return tokenVerifyMsg{err: err}
-		}
-		if err := app.SaveOAuthToken(token); err != nil {
-			return tokenVerifyMsg{err: err}
-		}
-		return tokenVerifyMsg{}
+func (m *model) updateDescription(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if key, ok := msg.(tea.KeyMsg); ok && key.Type == tea.KeyEnter {
+		m.descriptionText = strings.TrimSpace(m.descInput.Value())
+		m.descInput.Blur()
+		m.startTagsStage()
+		return m, nil
 	}
+	var cmd tea.Cmd
+	m.descInput, cmd = m.descInput.Update(msg)
+	return m, cmd
 }
 
-func tokenSpinnerCmd() tea.Cmd {
-	return tea.Tick(120*time.Millisecond, func(time.Time) tea.Msg {
-		return tokenSpinnerMsg{}
-	})
+func (m *model) updateTags(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if key, ok := msg.(tea.KeyMsg); ok && key.Type == tea.KeyEnter {
+		m.tagsText = strings.TrimSpace(m.tagsInput.Value())
+		m.tagsInput.Blur()
+		m.startNotifyStage()
+		return m, nil
+	}
+	var cmd tea.Cmd
+	m.tagsInput, cmd = m.tagsInput.Update(msg)
+	return m, cmd
 }
 
 func (m *model) updateScheduleInput(msg tea.Msg) (tea.Model, tea.Cmd) {
@@ -1426,6 +3586,10 @@ func (m *model) updateScheduleInput(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.inputError = "Enter date as YYYY-MM-DD."
 				return m, cmd
 			}
+			if dateInPast(value, app.ScheduleTimezone()) {
+				m.inputError = "That date is in the past."
+				return m, cmd
+			}
 			m.schedule.Date = value
 			m.startScheduleTimeStage()
 			return m, cmd
@@ -1434,6 +3598,117 @@ func (m *model) updateScheduleInput(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.inputError = ""
 		}
 		return m, cmd
+	case stageStartDate:
+		key, ok := msg.(tea.KeyMsg)
+		prev := m.startInput.Value()
+		var cmd tea.Cmd
+		m.startInput, cmd = m.startInput.Update(msg)
+		if !ok {
+			return m, cmd
+		}
+		if key.Type == tea.KeyEnter {
+			value := strings.TrimSpace(m.startInput.Value())
+			if value != "" {
+				start, err := scheduler.ParseStartDate(value, m.schedule.Timezone)
+				if err != nil {
+					m.inputError = "Enter the start date as YYYY-MM-DD, or leave it blank."
+					return m, cmd
+				}
+				if start.AddDate(0, 0, 1).Before(time.Now()) {
+					m.inputError = "Start date is in the past."
+					return m, cmd
+				}
+			}
+			m.schedule.StartDate = value
+			return m, m.finishEdit()
+		}
+		if m.startInput.Value() != prev {
+			m.inputError = ""
+		}
+		return m, cmd
+	case stageScheduleInterval:
+		key, ok := msg.(tea.KeyMsg)
+		prev := m.everyInput.Value()
+		var cmd tea.Cmd
+		m.everyInput, cmd = m.everyInput.Update(msg)
+		if !ok {
+			return m, cmd
+		}
+		if key.Type == tea.KeyEnter {
+			minutes, err := scheduler.ParseInterval(m.everyInput.Value())
+			if err != nil {
+				m.inputError = err.Error()
+				return m, cmd
+			}
+			m.schedule.Interval = minutes
+			m.schedule.Timezone = app.ScheduleTimezone()
+			return m, m.finishSchedule()
+		}
+		if m.everyInput.Value() != prev {
+			m.inputError = ""
+		}
+		return m, cmd
+	case stageScheduleCron:
+		key, ok := msg.(tea.KeyMsg)
+		prev := m.cronInput.Value()
+		var cmd tea.Cmd
+		m.cronInput, cmd = m.cronInput.Update(msg)
+		if !ok {
+			return m, cmd
+		}
+		if key.Type == tea.KeyEnter {
+			expr := strings.Join(strings.Fields(m.cronInput.Value()), " ")
+			if err := scheduler.ValidateCron(expr); err != nil {
+				m.inputError = err.Error()
+				return m, cmd
+			}
+			m.schedule.Cron = expr
+			m.schedule.Timezone = app.ScheduleTimezone()
+			entry := scheduler.ScheduleEntry{
+				Schedule: scheduler.Schedule{Type: "cron", Cron: expr},
+				Timezone: m.schedule.Timezone,
+			}
+			if _, err := scheduler.NextRun(entry, time.Now()); err != nil {
+				m.inputError = err.Error()
+				return m, cmd
+			}
+			return m, m.finishSchedule()
+		}
+		if m.cronInput.Value() != prev {
+			m.inputError = ""
+		}
+		return m, cmd
+	case stageSunLocation:
+		key, ok := msg.(tea.KeyMsg)
+		prev := m.locInput.Value()
+		var cmd tea.Cmd
+		m.locInput, cmd = m.locInput.Update(msg)
+		if !ok {
+			return m, cmd
+		}
+		if key.Type == tea.KeyEnter {
+			lat, lon, err := scheduler.ParseCoordinates(m.locInput.Value())
+			if err != nil {
+				m.inputError = "Enter location as latitude, longitude (e.g. 37.77, -122.42)."
+				return m, cmd
+			}
+			m.schedule.Latitude = lat
+			m.schedule.Longitude = lon
+			m.schedule.Timezone = app.ScheduleTimezone()
+			entry := scheduler.ScheduleEntry{
+				Schedule: scheduler.Schedule{Type: "sun", Event: m.schedule.Event, Latitude: lat, Longitude: lon},
+				Timezone: m.schedule.Timezone,
+			}
+			if _, err := scheduler.NextRun(entry, time.Now()); err != nil {
+				m.inputError = err.Error()
+				return m, cmd
+			}
+			return m, m.finishSchedule()
+		}
+		if m.locInput.Value() != prev {
+			m.inputError = ""
+		}
+		return m, cmd
 	case stageScheduleTime:
 		key, ok := msg.(tea.KeyMsg)
 		if !ok {
@@ -1443,20 +3718,46 @@ func (m *model) updateScheduleInput(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 		if key.Type == tea.KeyEnter {
 			value := strings.TrimSpace(m.timeInput.Value())
-			if !isValidTime(value) {
-				m.inputError = "Enter time as HH:MM (24-hour)."
-				return m, nil
+			if m.allowsMultipleTimes() {
+				times, err := scheduler.ParseTimes(value)
+				if err != nil {
+					m.inputError = "Enter times as HH:MM (24-hour), separated by commas."
+					return m, nil
+				}
+				m.schedule.Time = times[0]
+				m.schedule.Times = nil
+				if len(times) > 1 {
+					m.schedule.Times = times
+				}
+			} else {
+				if !isValidTime(value) {
+					m.inputError = "Enter time as HH:MM (24-hour)."
+					return m, nil
+				}
+				m.schedule.Time = value
+				m.schedule.Times = nil
 			}
-			m.schedule.Time = value
-			m.schedule.Timezone = time.Now().Location().String()
+			m.schedule.Timezone = app.ScheduleTimezone()
 			if m.schedule.Type == "once" {
 				if err := validateOnceSchedule(m.schedule.Date, m.schedule.Time, m.schedule.Timezone); err != nil {
 					m.inputError = err.Error()
+					if errors.Is(err, errTimeInPast) {
+						m.inputError = "That time is in the past."
+					}
 					return m, nil
 				}
 			}
-			m.finishResult()
-			return m, tea.Quit
+			return m, m.finishSchedule()
+		}
+
+		if m.allowsMultipleTimes() {
+			prev := m.timeInput.Value()
+			var cmd tea.Cmd
+			m.timeInput, cmd = m.timeInput.Update(msg)
+			if m.timeInput.Value() != prev {
+				m.inputError = ""
+			}
+			return m, cmd
 		}
 
 		value, pos, changed := applyTimeMask(m.timeInput.Value(), m.timeInput.Position(), key)
@@ -1497,6 +3798,56 @@ func (m *model) startProjectStage() {
 	m.setProjectItems()
 }
 
+func (m *model) startProjectPathStage() {
+	m.stage = stageProjectPath
+	m.inputError = ""
+	m.searchInput.Blur()
+	m.promptInput.Blur()
+	if m.manualProject {
+		m.pathInput.SetValue(m.project.Path)
+	}
+	m.pathInput.CursorEnd()
+	m.pathInput.Focus()
+}
+
+func (m *model) updateProjectPath(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if key, ok := msg.(tea.KeyMsg); ok && key.Type == tea.KeyEnter {
+		dir, err := app.NormalizePath(strings.TrimSpace(m.pathInput.Value()))
+		if err != nil {
+			m.inputError = err.Error()
+			return m, nil
+		}
+		if !scheduler.IsValidWorkDir(dir) {
+			m.inputError = "Enter an existing directory."
+			return m, nil
+		}
+		if app.IsWakeClaudeInternalPath(dir) {
+			m.inputError = "That is WakeClaude's own data directory; pick a project outside it."
+			return m, nil
+		}
+		if existing := m.findProject(dir); existing.Path != "" {
+			m.inputError = "Claude already knows this project; pick it from the list."
+			return m, nil
+		}
+		m.pathInput.Blur()
+		m.manualProject = true
+		m.project = app.Project{Path: dir, CWD: dir, DisplayName: app.HumanizePath(dir)}
+		m.sessions = nil
+		m.selectedSess = nil
+		m.selectedNew = true
+		m.selectedFork = false
+		m.startPromptStage()
+		return m, nil
+	}
+	prev := m.pathInput.Value()
+	var cmd tea.Cmd
+	m.pathInput, cmd = m.pathInput.Update(msg)
+	if m.pathInput.Value() != prev {
+		m.inputError = ""
+	}
+	return m, cmd
+}
+
 func (m *model) startPromptStage() {
 	m.stage = stagePrompt
 	m.inputError = ""
@@ -1507,6 +3858,14 @@ func (m *model) startPromptStage() {
 	m.promptInput.Focus()
 }
 
+func (m *model) startResumeModeStage() {
+	m.stage = stageResumeMode
+	m.inputError = ""
+	m.resetCursor()
+	m.promptInput.Blur()
+	m.setResumeModeItems()
+}
+
 func (m *model) startModelStage() {
 	m.stage = stageModels
 	m.inputError = ""
@@ -1516,6 +3875,43 @@ func (m *model) startModelStage() {
 	m.setModelItems()
 }
 
+func (m *model) startCustomModelStage() {
+	m.stage = stageCustomModel
+	m.inputError = ""
+	m.searchInput.Blur()
+	m.modelInput.SetValue("")
+	if m.isCustomModel() {
+		m.modelInput.SetValue(m.selectedModel.Value)
+	}
+	m.modelInput.Focus()
+	m.modelInput.CursorEnd()
+}
+
+func (m *model) updateCustomModel(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if key, ok := msg.(tea.KeyMsg); ok && key.Type == tea.KeyEnter {
+		value := strings.TrimSpace(m.modelInput.Value())
+		if value == "" {
+			m.inputError = "Enter a model name, or press esc to pick one from the list."
+			return m, nil
+		}
+		if strings.ContainsAny(value, " \t") {
+			m.inputError = "Model names have no spaces."
+			return m, nil
+		}
+		m.modelInput.Blur()
+		m.selectedModel = m.findModel(value)
+		m.startPermissionModeStage()
+		return m, nil
+	}
+	prev := m.modelInput.Value()
+	var cmd tea.Cmd
+	m.modelInput, cmd = m.modelInput.Update(msg)
+	if m.modelInput.Value() != prev {
+		m.inputError = ""
+	}
+	return m, cmd
+}
+
 func (m *model) startPermissionModeStage() {
 	m.stage = stagePermissionMode
 	m.inputError = ""
@@ -1525,6 +3921,59 @@ func (m *model) startPermissionModeStage() {
 	m.setPermissionModeItems()
 }
 
+func (m *model) startHookStage(target stage) {
+	m.stage = target
+	m.inputError = ""
+	m.searchInput.Blur()
+	m.promptInput.Blur()
+	m.hookInput.Placeholder = "e.g. git pull --ff-only"
+	m.hookInput.SetValue(m.preCommandText)
+	if target == stagePostCommand {
+		m.hookInput.Placeholder = "e.g. git commit -am 'nightly run'"
+		m.hookInput.SetValue(m.postCommandText)
+	}
+	m.hookInput.Focus()
+	m.hookInput.CursorEnd()
+}
+
+func (m *model) startEnvStage() {
+	m.stage = stageEnv
+	m.inputError = ""
+	m.searchInput.Blur()
+	m.hookInput.Blur()
+	m.envInput.SetValue(m.envText)
+	m.envInput.Focus()
+}
+
+func (m *model) startDescriptionStage() {
+	m.stage = stageDescription
+	m.inputError = ""
+	m.searchInput.Blur()
+	m.promptInput.Blur()
+	m.descInput.SetValue(m.descriptionText)
+	m.descInput.Focus()
+	m.descInput.CursorEnd()
+}
+
+func (m *model) startTagsStage() {
+	m.stage = stageTags
+	m.inputError = ""
+	m.searchInput.Blur()
+	m.promptInput.Blur()
+	m.tagsInput.SetValue(m.tagsText)
+	m.tagsInput.Focus()
+	m.tagsInput.CursorEnd()
+}
+
+func (m *model) startNotifyStage() {
+	m.stage = stageNotify
+	m.inputError = ""
+	m.resetCursor()
+	m.promptInput.Blur()
+	m.searchInput.Blur()
+	m.setNotifyItems()
+}
+
 func (m *model) startScheduleTypeStage() {
 	m.stage = stageScheduleType
 	m.inputError = ""
@@ -1561,6 +4010,79 @@ func (m *model) startScheduleWeekdayStage() {
 	m.setWeekdayItems()
 }
 
+func (m *model) startScheduleDayStage() {
+	m.stage = stageScheduleDay
+	m.inputError = ""
+	m.resetCursor()
+	m.searchInput.Focus()
+	m.promptInput.Blur()
+	m.dateInput.Blur()
+	m.timeInput.Blur()
+	m.setMonthDayItems()
+}
+
+func (m *model) startSunEventStage() {
+	m.stage = stageSunEvent
+	m.inputError = ""
+	m.resetCursor()
+	m.searchInput.Focus()
+	m.promptInput.Blur()
+	m.locInput.Blur()
+	m.setSunEventItems()
+}
+
+func (m *model) startScheduleCronStage() {
+	m.stage = stageScheduleCron
+	m.inputError = ""
+	m.searchInput.Blur()
+	m.promptInput.Blur()
+	m.cronInput.Focus()
+	m.cronInput.SetValue(m.schedule.Cron)
+	m.cronInput.CursorEnd()
+}
+
+// finishSchedule saves a one-time schedule, and asks a recurring one for an
+// optional start date first.
+func (m *model) finishSchedule() tea.Cmd {
+	if m.schedule.Type == "once" {
+		return m.finishEdit()
+	}
+	m.stage = stageStartDate
+	m.inputError = ""
+	m.searchInput.Blur()
+	m.promptInput.Blur()
+	m.timeInput.Blur()
+	m.startInput.Focus()
+	m.startInput.SetValue(m.schedule.StartDate)
+	m.startInput.CursorEnd()
+	return nil
+}
+
+func (m *model) startScheduleIntervalStage() {
+	m.stage = stageScheduleInterval
+	m.inputError = ""
+	m.searchInput.Blur()
+	m.promptInput.Blur()
+	m.everyInput.Focus()
+	m.everyInput.SetValue("")
+	if m.schedule.Interval > 0 {
+		m.everyInput.SetValue((time.Duration(m.schedule.Interval) * time.Minute).String())
+	}
+	m.everyInput.CursorEnd()
+}
+
+func (m *model) startSunLocationStage() {
+	m.stage = stageSunLocation
+	m.inputError = ""
+	m.searchInput.Blur()
+	m.promptInput.Blur()
+	m.locInput.Focus()
+	if m.schedule.Latitude != 0 || m.schedule.Longitude != 0 {
+		m.locInput.SetValue(fmt.Sprintf("%g, %g", m.schedule.Latitude, m.schedule.Longitude))
+	}
+	m.locInput.CursorEnd()
+}
+
 func (m *model) startScheduleTimeStage() {
 	m.stage = stageScheduleTime
 	m.inputError = ""
@@ -1568,9 +4090,22 @@ func (m *model) startScheduleTimeStage() {
 	m.promptInput.Blur()
 	m.dateInput.Blur()
 	m.timeInput.Focus()
+	if m.allowsMultipleTimes() {
+		m.timeInput.CharLimit = 64
+		m.timeInput.Placeholder = "HH:MM, HH:MM"
+		if times := scheduler.ScheduleTimes(scheduler.Schedule{Time: m.schedule.Time, Times: m.schedule.Times}); len(times) > 0 {
+			m.timeInput.SetValue(strings.Join(times, ", "))
+		} else if strings.TrimSpace(m.timeInput.Value()) == "" {
+			m.timeInput.SetValue(time.Now().Format("15:04"))
+		}
+		m.timeInput.CursorEnd()
+		return
+	}
+	m.timeInput.CharLimit = 5
+	m.timeInput.Placeholder = "HH:MM"
 	if strings.TrimSpace(m.schedule.Time) != "" && isValidTime(m.schedule.Time) {
 		m.timeInput.SetValue(normalizeTimeValue(m.schedule.Time))
-	} else if strings.TrimSpace(m.timeInput.Value()) == "" || !isValidTime(m.timeInput.Value()) {
+	} else if strings.TrimSpace(m.timeInput.Value()) == "" || !isValidTime(strings.TrimSpace(m.timeInput.Value())) {
 		m.timeInput.SetValue(normalizeTimeValue(time.Now().Format("15:04")))
 	} else {
 		m.timeInput.SetValue(normalizeTimeValue(m.timeInput.Value()))
@@ -1581,14 +4116,28 @@ func (m *model) startScheduleTimeStage() {
 func (m *model) startScheduleListStage() {
 	m.stage = stageScheduleList
 	m.inputError = ""
+	m.armedDelete = ""
 	m.resetCursor()
 	m.searchInput.Focus()
 	m.setScheduleItems()
 }
 
-func (m *model) startLogsStage() {
+func (m *model) startLogsStage(scheduleID string) {
+	m.logSessionsOnly = false
+	m.openLogs(scheduleID)
+}
+
+// startSessionsStage lists the sessions a schedule's runs created or
+// continued, newest first, one row per session.
+func (m *model) startSessionsStage(scheduleID string) {
+	m.logSessionsOnly = true
+	m.openLogs(scheduleID)
+}
+
+func (m *model) openLogs(scheduleID string) {
 	m.stage = stageLogs
 	m.inputError = ""
+	m.logScheduleID = scheduleID
 	m.logDetailIndex = -1
 	m.logDetailOutput = ""
 	m.logDetailOutputErr = ""
@@ -1613,10 +4162,35 @@ func (m *model) startSetupTokenStage() {
 }
 
 func (m *model) startEditFlow(entry scheduler.ScheduleEntry) {
+	m.loadEditState(entry)
+	m.promptInput.SetValue(entry.Prompt)
+	m.startPromptStage()
+}
+
+// startDuplicateFlow walks through the edit steps for entry, but finishing
+// creates a new schedule instead of changing entry.
+func (m *model) startDuplicateFlow(entry scheduler.ScheduleEntry) {
+	m.startEditFlow(entry)
+	m.editID = ""
+	m.duplicate = &entry
+}
+
+// startEditStage edits a single stage of a schedule; finishing it saves the
+// edit with every other field as it was. Only stagePrompt is supported.
+func (m *model) startEditStage(entry scheduler.ScheduleEntry, target stage) {
+	m.startEditFlow(entry)
+	m.editOnly = target
+}
+
+func (m *model) loadEditState(entry scheduler.ScheduleEntry) {
 	m.editID = entry.ID
+	m.duplicate = nil
+	m.editOnly = stageMain
+	m.manualProject = false
+	m.converting = false
 	m.project = m.findProject(entry.ProjectPath)
 	if m.project.Path == "" {
-		m.project = app.Project{Path: entry.ProjectPath, DisplayName: app.HumanizePath(entry.ProjectPath)}
+		m.project = app.Project{Path: entry.ProjectPath, DisplayName: app.DisplayProjectPath(entry.ProjectPath)}
 	}
 
 	sessions, err := app.ListSessions(entry.ProjectPath)
@@ -1625,6 +4199,7 @@ func (m *model) startEditFlow(entry scheduler.ScheduleEntry) {
 	}
 
 	m.selectedNew = entry.NewSession
+	m.selectedFork = !entry.NewSession && entry.ForkSession
 	m.selectedSess = nil
 	if !entry.NewSession && entry.SessionID != "" {
 		for i := range m.sessions {
@@ -1638,23 +4213,127 @@ func (m *model) startEditFlow(entry scheduler.ScheduleEntry) {
 		}
 	}
 
-	m.selectedModel = m.findModel(entry.Model)
-	if entry.PermissionMode != "" && entry.PermissionMode != "default" {
-		m.selectedPerm = entry.PermissionMode
-	} else {
-		m.selectedPerm = "acceptEdits"
-	}
-	m.promptText = entry.Prompt
-	m.schedule = Schedule{
-		Type:     entry.Schedule.Type,
-		Date:     entry.Schedule.Date,
-		Time:     entry.Schedule.Time,
-		Weekday:  entry.Schedule.Weekday,
-		Timezone: entry.Timezone,
+	m.selectedModel = m.findModel(entry.Model)
+	if entry.PermissionMode != "" {
+		m.selectedPerm = entry.PermissionMode
+	} else {
+		m.selectedPerm = "acceptEdits"
+	}
+	m.promptText = entry.Prompt
+	m.preCommandText = entry.PreCommand
+	m.postCommandText = entry.PostCommand
+	m.envText = scheduler.FormatEnv(entry.Env)
+	m.descriptionText = entry.Description
+	m.tagsText = strings.Join(entry.Tags, ", ")
+	m.selectedNote = entry.Notify
+	m.schedule = Schedule{
+		Type:      entry.Schedule.Type,
+		Date:      entry.Schedule.Date,
+		Time:      entry.Schedule.Time,
+		Times:     entry.Schedule.Times,
+		Weekday:   entry.Schedule.Weekday,
+		Day:       entry.Schedule.Day,
+		Cron:      entry.Schedule.Cron,
+		Interval:  entry.Schedule.IntervalMinutes,
+		StartDate: scheduler.StartDate(entry, time.Now()),
+		Event:     entry.Schedule.Event,
+		Latitude:  entry.Schedule.Latitude,
+		Longitude: entry.Schedule.Longitude,
+		Timezone:  entry.Timezone,
+	}
+}
+
+// finishEdit saves a new schedule right away; an edit first shows what it
+// changes, on stageConfirmEdit.
+func (m *model) finishEdit() tea.Cmd {
+	if m.editID == "" {
+		m.finishResult()
+		return tea.Quit
+	}
+	m.confirmReturn = m.stage
+	m.stage = stageConfirmEdit
+	m.inputError = ""
+	m.searchInput.Blur()
+	m.promptInput.Blur()
+	m.timeInput.Blur()
+	m.startInput.Blur()
+	m.nextInput.Blur()
+	return nil
+}
+
+func (m *model) returnFromConfirmEdit() {
+	m.stage = m.confirmReturn
+	switch m.stage {
+	case stagePrompt:
+		m.promptInput.Focus()
+	case stageScheduleTime:
+		m.timeInput.Focus()
+	case stageStartDate:
+		m.startInput.Focus()
+	case stageNextRun:
+		m.nextInput.Focus()
+	}
+}
+
+func (m *model) updateConfirmEdit(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if key, ok := msg.(tea.KeyMsg); ok && key.Type == tea.KeyEnter {
+		m.finishResult()
+		return m, tea.Quit
 	}
+	return m, nil
+}
 
-	m.promptInput.SetValue(entry.Prompt)
-	m.startPromptStage()
+func (m model) renderConfirmEdit(b *strings.Builder, width int) {
+	current, ok := m.findSchedule(m.editID)
+	if !ok {
+		b.WriteString(renderLine("Schedule not found.", width))
+		b.WriteString("\n")
+		b.WriteString("esc back | q quit\n")
+		return
+	}
+	edited := current
+	edited.Prompt = m.promptText
+	edited.Model = m.selectedModel.Value
+	edited.PermissionMode = m.selectedPerm
+	edited.Timezone = m.schedule.Timezone
+	edited.Schedule = scheduler.Schedule{
+		Type:            m.schedule.Type,
+		Date:            m.schedule.Date,
+		Time:            m.schedule.Time,
+		Times:           m.schedule.Times,
+		Weekday:         m.schedule.Weekday,
+		Day:             m.schedule.Day,
+		Cron:            m.schedule.Cron,
+		IntervalMinutes: m.schedule.Interval,
+		Event:           m.schedule.Event,
+		Latitude:        m.schedule.Latitude,
+		Longitude:       m.schedule.Longitude,
+	}
+
+	b.WriteString(renderLine("Review the changes before saving.", width))
+	b.WriteString("\n")
+	b.WriteString(renderLine(fmt.Sprintf("Project: %s", m.projectLabel()), width))
+	b.WriteString("\n")
+	changes := 0
+	for _, field := range []struct{ label, before, after string }{
+		{"Prompt", scheduler.Preview(current.Prompt, 60), scheduler.Preview(edited.Prompt, 60)},
+		{"Schedule", scheduler.ScheduleLabel(current), scheduler.ScheduleLabel(edited)},
+		{"Model", current.Model, edited.Model},
+		{"Permission", current.PermissionMode, edited.PermissionMode},
+	} {
+		line := fmt.Sprintf("%s: %s (unchanged)", field.label, field.before)
+		if field.before != field.after {
+			line = fmt.Sprintf("%s: %s -> %s", field.label, field.before, field.after)
+			changes++
+		}
+		b.WriteString(renderLine(line, width))
+		b.WriteString("\n")
+	}
+	if changes == 0 {
+		b.WriteString(renderLine("None of these changed.", width))
+		b.WriteString("\n")
+	}
+	b.WriteString("enter save | esc back | q quit\n")
 }
 
 func (m *model) finishResult() {
@@ -1662,11 +4341,19 @@ func (m *model) finishResult() {
 	if projectPath == "" {
 		projectPath = m.project.Path
 	}
+	// Already validated when the env stage was left.
+	env, _ := scheduler.ParseEnv(m.envText)
 	draft := &Draft{
 		ProjectPath: projectPath,
 		Model:       m.selectedModel.Value,
 		Permission:  m.selectedPerm,
 		Prompt:      m.promptText,
+		PreCommand:  m.preCommandText,
+		PostCommand: m.postCommandText,
+		Env:         env,
+		Description: m.descriptionText,
+		Tags:        scheduler.ParseTags(m.tagsText),
+		Notify:      m.selectedNote,
 		Schedule:    m.schedule,
 	}
 	if m.selectedNew {
@@ -1674,6 +4361,10 @@ func (m *model) finishResult() {
 	} else if m.selectedSess != nil {
 		draft.SessionID = m.selectedSess.ID
 		draft.SessionPath = m.selectedSess.Path
+		draft.ForkSession = m.selectedFork
+	}
+	if m.duplicate != nil {
+		copyHiddenOptions(draft, *m.duplicate)
 	}
 
 	kind := ActionSchedule
@@ -1687,18 +4378,51 @@ func (m *model) finishResult() {
 	}
 }
 
+// copyHiddenOptions carries over the settings the tui has no step for, so a
+// duplicate keeps them.
+func copyHiddenOptions(draft *Draft, entry scheduler.ScheduleEntry) {
+	draft.Group = entry.GroupName
+	draft.NotifySound = entry.NotifySound
+	draft.HomeDir = entry.HomeOverride
+	draft.ClaudeBinary = entry.ClaudeBinary
+	draft.MinBattery = entry.MinBatteryPercent
+	draft.RequireAC = entry.RequireAC
+	if entry.RequireNetwork {
+		draft.NetworkHost = entry.NetworkHost
+		if draft.NetworkHost == "" {
+			draft.NetworkHost = scheduler.DefaultNetworkHost
+		}
+	}
+	draft.RequireDirty = entry.RequireDirty
+	draft.Retries = entry.Retries
+	draft.RetryDelay = entry.RetryDelay
+	draft.JSONOutput = entry.OutputFormat == "json"
+	draft.LowPriority = entry.LowPriority
+	draft.ProcessType = entry.ProcessType
+}
+
 func (m *model) applyFilter() {
 	query := strings.ToLower(strings.TrimSpace(m.searchInput.Value()))
-	if query == "" {
+	if content, ok := m.sessionContentQuery(); ok {
+		m.items = m.contentMatches(content)
+	} else if query == "" {
 		m.items = append([]listItem(nil), m.all...)
 	} else {
+		var tags []string
+		group := ""
+		if m.stage == stageScheduleList {
+			tags, group, query = splitTagQuery(query)
+		}
 		filtered := make([]listItem, 0, len(m.all))
 		for _, item := range m.all {
 			if item.pinned {
 				filtered = append(filtered, item)
 				continue
 			}
-			if strings.Contains(item.filter, query) {
+			if group != "" && !strings.EqualFold(item.group, group) {
+				continue
+			}
+			if hasTags(item.tags, tags) && strings.Contains(item.filter, query) {
 				filtered = append(filtered, item)
 			}
 		}
@@ -1709,6 +4433,60 @@ func (m *model) applyFilter() {
 	m.ensureCursorVisible()
 }
 
+// contentMatches keeps the pinned "new session" item while a search is still
+// running, so the list never looks empty for a query that may yet match.
+func (m *model) contentMatches(query string) []listItem {
+	if query == "" {
+		return append([]listItem(nil), m.all...)
+	}
+	hits := m.contentHits[m.contentKey(query)]
+	items := make([]listItem, 0, len(m.all))
+	for _, item := range m.all {
+		if item.pinned {
+			items = append(items, item)
+			continue
+		}
+		if item.index >= 0 && item.index < len(m.sessions) && hits[m.sessions[item.index].Path] {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+func splitTagQuery(query string) ([]string, string, string) {
+	var tags []string
+	group := ""
+	rest := make([]string, 0, 2)
+	for _, field := range strings.Fields(query) {
+		if len(field) > 1 && strings.HasPrefix(field, "#") {
+			tags = append(tags, strings.TrimPrefix(field, "#"))
+			continue
+		}
+		if len(field) > 1 && strings.HasPrefix(field, "@") {
+			group = strings.TrimPrefix(field, "@")
+			continue
+		}
+		rest = append(rest, field)
+	}
+	return tags, group, strings.Join(rest, " ")
+}
+
+func hasTags(itemTags, wanted []string) bool {
+	for _, tag := range wanted {
+		found := false
+		for _, itemTag := range itemTags {
+			if strings.EqualFold(itemTag, tag) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
 func (m *model) resetCursor() {
 	m.cursor = 0
 	m.offset = 0
@@ -1724,8 +4502,9 @@ func (m *model) selectCurrent() tea.Cmd {
 	case itemMain:
 		switch item.meta {
 		case "new":
+			m.manualProject = false
 			if m.projectsErr != nil || len(m.projects) == 0 {
-				m.inputError = "No Claude projects found. Run Claude once to create them."
+				m.startProjectPathStage()
 				return nil
 			}
 			m.startProjectStage()
@@ -1734,7 +4513,12 @@ func (m *model) selectCurrent() tea.Cmd {
 			m.startScheduleListStage()
 			return nil
 		case "logs":
-			m.startLogsStage()
+			m.startLogsStage("")
+			return nil
+		case "stats":
+			m.stage = stageStats
+			m.inputError = ""
+			m.searchInput.Blur()
 			return nil
 		case "token":
 			m.startSetupTokenStage()
@@ -1743,7 +4527,11 @@ func (m *model) selectCurrent() tea.Cmd {
 			m.err = ErrUserQuit
 			return tea.Quit
 		}
+	case itemManualPath:
+		m.startProjectPathStage()
+		return nil
 	case itemProject:
+		m.manualProject = false
 		project := m.projects[item.index]
 		sessions, err := app.ListSessions(project.Path)
 		if err != nil {
@@ -1759,12 +4547,20 @@ func (m *model) selectCurrent() tea.Cmd {
 	case itemNewSession:
 		m.selectedSess = nil
 		m.selectedNew = true
+		m.selectedFork = false
 		m.startPromptStage()
 		return nil
 	case itemSession:
 		session := m.sessions[item.index]
 		m.selectedSess = &session
 		m.selectedNew = false
+		m.startResumeModeStage()
+		return nil
+	case itemResumeMode:
+		if item.index < 0 || item.index >= len(resumeModeOptions) {
+			return nil
+		}
+		m.selectedFork = resumeModeOptions[item.index].Value == "fork"
 		m.startPromptStage()
 		return nil
 	case itemModel:
@@ -1775,12 +4571,22 @@ func (m *model) selectCurrent() tea.Cmd {
 		m.selectedModel = option
 		m.startPermissionModeStage()
 		return nil
+	case itemCustomModel:
+		m.startCustomModelStage()
+		return nil
 	case itemPermissionMode:
 		if item.index < 0 || item.index >= len(permissionModeOptions) {
 			return nil
 		}
 		option := permissionModeOptions[item.index]
 		m.selectedPerm = option.Value
+		m.startDescriptionStage()
+		return nil
+	case itemNotify:
+		if item.index < 0 || item.index >= len(notifyOptions) {
+			return nil
+		}
+		m.selectedNote = notifyOptions[item.index].Value
 		m.startScheduleTypeStage()
 		return nil
 	case itemScheduleType:
@@ -1791,17 +4597,35 @@ func (m *model) selectCurrent() tea.Cmd {
 		m.schedule.Type = option.Value
 		m.schedule.Date = ""
 		m.schedule.Time = ""
+		m.schedule.Times = nil
 		m.schedule.Weekday = ""
+		m.schedule.Day = 0
+		m.schedule.Event = ""
 		m.schedule.Timezone = ""
 		switch option.Value {
 		case "once":
 			m.startScheduleDateStage()
 		case "weekly":
 			m.startScheduleWeekdayStage()
+		case "monthly":
+			m.startScheduleDayStage()
+		case "sun":
+			m.startSunEventStage()
+		case "cron":
+			m.startScheduleCronStage()
+		case "interval":
+			m.startScheduleIntervalStage()
+		case "login":
+			m.schedule.Timezone = app.ScheduleTimezone()
+			return m.finishSchedule()
 		default:
 			m.startScheduleTimeStage()
 		}
 		return nil
+	case itemMonthDay:
+		m.schedule.Day = item.index
+		m.startScheduleTimeStage()
+		return nil
 	case itemWeekday:
 		if item.index < 0 || item.index >= len(weekdayOptions) {
 			return nil
@@ -1810,11 +4634,25 @@ func (m *model) selectCurrent() tea.Cmd {
 		m.schedule.Weekday = option.Label
 		m.startScheduleTimeStage()
 		return nil
+	case itemSunEvent:
+		if item.index < 0 || item.index >= len(sunEventOptions) {
+			return nil
+		}
+		m.schedule.Event = sunEventOptions[item.index].Value
+		m.startSunLocationStage()
+		return nil
 	case itemSchedule:
 		if item.index < 0 || item.index >= len(m.schedules) {
 			return nil
 		}
 		entry := m.schedules[item.index]
+		if _, upcoming := nextRunForList(entry, time.Now()); !upcoming && entry.Schedule.Type == "once" {
+			// Expired one-time schedule: go straight to picking a new date.
+			m.loadEditState(entry)
+			m.schedule.Date = time.Now().Format("2006-01-02")
+			m.startScheduleDateStage()
+			return nil
+		}
 		m.startEditFlow(entry)
 		return nil
 	case itemLog:
@@ -1822,6 +4660,7 @@ func (m *model) selectCurrent() tea.Cmd {
 			return nil
 		}
 		m.logDetailIndex = item.index
+		m.inputError = ""
 		m.logDetailOutput = ""
 		m.logDetailOutputErr = ""
 		entry := m.logs[item.index]
@@ -1834,11 +4673,30 @@ func (m *model) selectCurrent() tea.Cmd {
 		}
 		m.stage = stageLogDetail
 		return nil
+	case itemPause:
+		if m.pendingPause == nil {
+			return nil
+		}
+		var until time.Time
+		if item.index >= 0 && item.index < len(pauseOptions) {
+			until = pauseOptions[item.index].until(time.Now())
+		}
+		m.action = Action{
+			Kind:        ActionPause,
+			ScheduleID:  m.pendingPause.ID,
+			PausedUntil: until,
+			GroupID:     m.pendingGroup,
+		}
+		return tea.Quit
 	case itemConfirm:
+		if m.clearingLogs {
+			return m.clearLogs(item.index)
+		}
 		if item.index == 0 && m.pendingDel != nil {
 			m.action = Action{
 				Kind:       ActionDelete,
 				ScheduleID: m.pendingDel.ID,
+				GroupID:    m.pendingGroup,
 			}
 			return tea.Quit
 		}
@@ -1898,13 +4756,15 @@ func (m model) headerLines() int {
 		lines += 1
 	case stageSessions:
 		lines += 2
+	case stageResumeMode:
+		lines += 5
 	case stageModels:
 		lines += 3
 	case stagePermissionMode:
 		lines += 5
-	case stageScheduleType:
+	case stageScheduleType, stageNotify:
 		lines += 5
-	case stageScheduleWeekday:
+	case stageScheduleWeekday, stageScheduleDay, stageSunEvent:
 		lines += 6
 	case stageScheduleList:
 		lines += 1
@@ -1914,14 +4774,21 @@ func (m model) headerLines() int {
 		lines += 6
 	case stageLogDetail:
 		lines += 6
-	case stageConfirmDelete:
+	case stageConfirmDelete, stagePause:
 		lines += 2
 	default:
 		lines += 1
 	}
+	switch m.stage {
+	case stagePermissionMode, stageScheduleType, stageNotify, stageScheduleWeekday, stageScheduleDay, stageSunEvent:
+		lines += max(0, len(m.promptHeaderLines(renderWidth(m.width)))-1)
+	}
 	if m.projectsErr != nil && m.stage == stageMain {
 		lines += 1
 	}
+	if text := m.expandedLogError(); text != "" {
+		lines += len(wrapWithIndent(text, max(10, renderWidth(m.width)-logErrorIndent), 0))
+	}
 	if m.usesSearch() {
 		lines += 2
 	} else {
@@ -1950,13 +4817,23 @@ func (m model) itemLines() int {
 	}
 }
 
+// The schedule list and logs have single-letter hotkeys, which only work
+// once esc or tab has left the search box.
+func (m model) hasListCommands() bool {
+	return m.stage == stageScheduleList || m.stage == stageLogs
+}
+
+func (m model) listSearching() bool {
+	return m.hasListCommands() && m.searchInput.Focused()
+}
+
 func (m model) usesSearch() bool {
 	switch m.stage {
 	case stageProjects, stageSessions, stageScheduleList, stageLogs:
 		return true
 	case stageMain, stageConfirmDelete:
 		return false
-	case stagePrompt, stageScheduleDate, stageScheduleTime:
+	case stagePrompt, stagePreCommand, stagePostCommand, stageEnv, stageCustomModel, stageDescription, stageTags, stageScheduleDate, stageScheduleTime, stageSunLocation, stageScheduleCron, stageScheduleInterval, stageStartDate:
 		return false
 	case stageSetupToken:
 		return false
@@ -2270,6 +5147,76 @@ func tokenSpinnerFrame(index int) string {
 	return frames[index%len(frames)]
 }
 
+// logTailBytes is plenty for the lines a screen can show.
+const logTailBytes = 16 * 1024
+
+func (m *model) startLogTail(entry scheduler.LogEntry) tea.Cmd {
+	m.tailEntry = entry
+	m.tailOutput = ""
+	m.tailSeq++
+	m.stage = stageLogTail
+	m.inputError = ""
+	m.searchInput.Blur()
+	if !m.refreshTail() {
+		return nil
+	}
+	return logTailCmd(m.tailSeq)
+}
+
+// refreshTail rereads the run's status and the end of its output; false
+// once the run has finished.
+func (m *model) refreshTail() bool {
+	if store, err := scheduler.DefaultStore(); err == nil {
+		if logs, err := store.LoadLogs(0); err == nil {
+			for _, entry := range logs {
+				if entry.ID == m.tailEntry.ID {
+					m.tailEntry = entry
+					break
+				}
+			}
+		}
+	}
+	m.tailOutput = string(scheduler.ReadTail(m.tailEntry.OutputPath, logTailBytes))
+	return m.tailEntry.Status == "running"
+}
+
+func (m model) renderLogTail(b *strings.Builder, width int) {
+	b.WriteString(renderLine(fmt.Sprintf("Prompt: %s", m.tailEntry.PromptPreview), width))
+	b.WriteString("\n")
+	status := runStatusMessage(m.tailEntry)
+	if m.tailEntry.Status == "running" {
+		status = fmt.Sprintf("%s (started %s)", status, scheduler.RelativeLabel(m.tailEntry.RanAt, time.Now()))
+	}
+	b.WriteString(renderLine(fmt.Sprintf("Status: %s", status), width))
+	b.WriteString("\n")
+	b.WriteString("\n")
+
+	lines := strings.Split(strings.TrimRight(m.tailOutput, "\n"), "\n")
+	if strings.TrimSpace(m.tailOutput) == "" {
+		lines = []string{"(no output yet)"}
+	}
+	if count := tailLineCount(m.height); len(lines) > count {
+		lines = lines[len(lines)-count:]
+	}
+	for _, line := range lines {
+		b.WriteString(renderLine(line, width))
+		b.WriteString("\n")
+	}
+	b.WriteString("\n")
+	if m.tailEntry.Status == "running" {
+		b.WriteString("following (updates every second) | esc back | q quit\n")
+		return
+	}
+	b.WriteString("esc back | q quit\n")
+}
+
+func tailLineCount(height int) int {
+	if height <= 0 {
+		return 20
+	}
+	return max(5, height-(len(asciiArtLines)+2)-6)
+}
+
 func readOutputSnippet(path string, max int) (string, error) {
 	if max <= 0 {
 		max = 2000
@@ -2301,6 +5248,99 @@ func readOutputSnippet(path string, max int) (string, error) {
 	return text, nil
 }
 
+type statsWindow struct {
+	Label string
+	since func(now time.Time) time.Time
+}
+
+var statsWindows = []statsWindow{
+	{Label: "today", since: func(now time.Time) time.Time {
+		return time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	}},
+	{Label: "last 7 days", since: func(now time.Time) time.Time { return now.AddDate(0, 0, -7) }},
+	{Label: "last 30 days", since: func(now time.Time) time.Time { return now.AddDate(0, 0, -30) }},
+	{Label: "all retained runs", since: func(time.Time) time.Time { return time.Time{} }},
+}
+
+type runTotals struct {
+	runs     int
+	duration time.Duration
+	preview  string
+}
+
+type runStats struct {
+	runTotals
+	statuses  map[string]int
+	schedules map[string]*runTotals
+	order     []string
+}
+
+func computeRunStats(logs []scheduler.LogEntry, since time.Time) runStats {
+	stats := runStats{
+		statuses:  make(map[string]int),
+		schedules: make(map[string]*runTotals),
+	}
+	for _, entry := range logs {
+		if entry.RanAt.Before(since) {
+			continue
+		}
+		duration := time.Duration(entry.DurationMs) * time.Millisecond
+		stats.runs++
+		stats.duration += duration
+		stats.statuses[entry.Status]++
+
+		perSchedule, ok := stats.schedules[entry.ScheduleID]
+		if !ok {
+			perSchedule = &runTotals{preview: scheduler.Preview(entry.PromptPreview, 40)}
+			stats.schedules[entry.ScheduleID] = perSchedule
+			stats.order = append(stats.order, entry.ScheduleID)
+		}
+		perSchedule.runs++
+		perSchedule.duration += duration
+	}
+	sort.SliceStable(stats.order, func(i, j int) bool {
+		return stats.schedules[stats.order[i]].duration > stats.schedules[stats.order[j]].duration
+	})
+	return stats
+}
+
+func (s runStats) statusLabel() string {
+	statuses := make([]string, 0, len(s.statuses))
+	for status := range s.statuses {
+		statuses = append(statuses, status)
+	}
+	sort.Strings(statuses)
+	parts := make([]string, 0, len(statuses))
+	for _, status := range statuses {
+		label := status
+		if label == "success" {
+			label = "ok"
+		} else if label == "" {
+			label = "unknown"
+		}
+		parts = append(parts, fmt.Sprintf("%s %d", label, s.statuses[status]))
+	}
+	return strings.Join(parts, " · ")
+}
+
+func runCountLabel(count int) string {
+	if count == 1 {
+		return "1 run"
+	}
+	return fmt.Sprintf("%d runs", count)
+}
+
+func formatRunDuration(d time.Duration) string {
+	d = d.Round(time.Second)
+	if d < time.Minute {
+		return fmt.Sprintf("%ds", int(d/time.Second))
+	}
+	if d < time.Hour {
+		return fmt.Sprintf("%dm%02ds", int(d/time.Minute), int(d%time.Minute/time.Second))
+	}
+	return fmt.Sprintf("%dh%02dm", int(d/time.Hour), int(d%time.Hour/time.Minute))
+}
+
 func sessionCountLabel(count int) string {
 	if count == 1 {
 		return "1 session"
@@ -2320,6 +5360,12 @@ type permissionModeOption struct {
 	Desc  string
 }
 
+type resumeModeOption struct {
+	Value string
+	Label string
+	Desc  string
+}
+
 type mainOption struct {
 	Label string
 	Meta  string
@@ -2329,6 +5375,7 @@ var mainOptions = []mainOption{
 	{Label: "Schedule a prompt", Meta: "new"},
 	{Label: "Manage scheduled prompts", Meta: "list"},
 	{Label: "View run logs", Meta: "logs"},
+	{Label: "Run stats", Meta: "stats"},
 	{Label: "Setup token", Meta: "token"},
 	{Label: "Quit", Meta: "exit"},
 }
@@ -2337,6 +5384,22 @@ var scheduleTypeOptions = []scheduleOption{
 	{Value: "once", Label: "One-time (pick date and time)", Meta: "once"},
 	{Value: "daily", Label: "Daily (pick time)", Meta: "daily"},
 	{Value: "weekly", Label: "Weekly (pick day and time)", Meta: "weekly"},
+	{Value: "monthly", Label: "Monthly (pick day of month and time)", Meta: "monthly"},
+	{Value: "interval", Label: "Every N hours / minutes (pick interval)", Meta: "interval"},
+	{Value: "cron", Label: "Cron expression (5 fields)", Meta: "cron"},
+	{Value: "sun", Label: "Sunrise / sunset (pick event and location)", Meta: "sun"},
+	{Value: "login", Label: "At login (no wake, no sudo)", Meta: "login"},
+}
+
+var notifyOptions = []scheduleOption{
+	{Value: "always", Label: "Always", Meta: "always"},
+	{Value: "failure", Label: "Only when a run fails", Meta: "failure"},
+	{Value: "never", Label: "Never (check the logs)", Meta: "never"},
+}
+
+var sunEventOptions = []scheduleOption{
+	{Value: "sunrise", Label: "Sunrise", Meta: "sunrise"},
+	{Value: "sunset", Label: "Sunset", Meta: "sunset"},
 }
 
 var permissionModeOptions = []permissionModeOption{
@@ -2355,6 +5418,47 @@ var permissionModeOptions = []permissionModeOption{
 		Label: "Bypass permissions",
 		Desc:  "Skip all permission prompts (advanced).",
 	},
+	{
+		Value: "default",
+		Label: "Default (ask)",
+		Desc:  "Claude's own default; anything that needs approval is refused.",
+	},
+}
+
+var resumeModeOptions = []resumeModeOption{
+	{
+		Value: "resume",
+		Label: "Continue this session",
+		Desc:  "Append the prompt to the existing conversation.",
+	},
+	{
+		Value: "fork",
+		Label: "Fork into a new session",
+		Desc:  "Branch a new session from this one; the original is left untouched.",
+	},
+}
+
+type pauseOption struct {
+	Label string
+	until func(now time.Time) time.Time
+}
+
+var pauseOptions = []pauseOption{
+	{Label: "For 1 hour", until: func(now time.Time) time.Time { return now.Add(time.Hour) }},
+	{Label: "For 2 hours", until: func(now time.Time) time.Time { return now.Add(2 * time.Hour) }},
+	{Label: "Until tomorrow", until: func(now time.Time) time.Time { return startOfDay(now).AddDate(0, 0, 1) }},
+	{Label: "Until Monday", until: func(now time.Time) time.Time {
+		days := (int(time.Monday) - int(now.Weekday()) + 7) % 7
+		if days == 0 {
+			days = 7
+		}
+		return startOfDay(now).AddDate(0, 0, days)
+	}},
+	{Label: "For 1 week", until: func(now time.Time) time.Time { return now.AddDate(0, 0, 7) }},
+}
+
+func startOfDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
 }
 
 var weekdayOptions = []scheduleOption{
@@ -2367,29 +5471,13 @@ var weekdayOptions = []scheduleOption{
 	{Value: "sunday", Label: "Sunday", Meta: "sun"},
 }
 
-func formatScheduleLabel(entry scheduler.ScheduleEntry) string {
-	switch entry.Schedule.Type {
-	case "daily":
-		if entry.Schedule.Time != "" {
-			return fmt.Sprintf("Daily %s", entry.Schedule.Time)
-		}
-		return "Daily"
-	case "weekly":
-		if entry.Schedule.Time != "" && entry.Schedule.Weekday != "" {
-			return fmt.Sprintf("Weekly %s %s", entry.Schedule.Weekday, entry.Schedule.Time)
-		}
-		if entry.Schedule.Weekday != "" {
-			return fmt.Sprintf("Weekly %s", entry.Schedule.Weekday)
-		}
-		return "Weekly"
-	case "once":
-		if entry.Schedule.Date != "" && entry.Schedule.Time != "" {
-			return fmt.Sprintf("Once %s %s", entry.Schedule.Date, entry.Schedule.Time)
+func sunEventLabel(event string) string {
+	for _, option := range sunEventOptions {
+		if option.Value == event {
+			return strings.ToLower(option.Label)
 		}
-		return "Once"
-	default:
-		return "Schedule"
 	}
+	return event
 }
 
 func formatAdded(t time.Time, now time.Time) string {
@@ -2407,14 +5495,30 @@ func formatDetailTime(t time.Time, now time.Time) string {
 	if t.IsZero() {
 		return ""
 	}
-	layout := "Jan 02 15:04"
+	return t.Local().Format(detailTimeLayout(t, now))
+}
+
+func detailTimeLayout(t time.Time, now time.Time) string {
 	if t.Year() != now.Year() {
-		layout = "Jan 02 2006 15:04"
+		return "Jan 02 2006 15:04"
+	}
+	return "Jan 02 15:04"
+}
+
+// hasClockTime reports whether the schedule is set to wall-clock times in
+// its own time zone (sun, interval and login schedules are not).
+func hasClockTime(entry scheduler.ScheduleEntry) bool {
+	switch entry.Schedule.Type {
+	case "once", "daily", "weekly", "monthly", "cron":
+		return true
 	}
-	return t.Local().Format(layout)
+	return false
 }
 
 func nextRunForList(entry scheduler.ScheduleEntry, now time.Time) (time.Time, bool) {
+	if entry.Schedule.Type == "login" {
+		return time.Time{}, true
+	}
 	if !entry.NextRun.IsZero() && entry.NextRun.After(now) {
 		return entry.NextRun, true
 	}
@@ -2425,7 +5529,72 @@ func nextRunForList(entry scheduler.ScheduleEntry, now time.Time) (time.Time, bo
 	return next, next.After(now)
 }
 
+// countdownLabel is how long until next, e.g. "in 3h 12m"; "" when it is
+// unset or past.
+func countdownLabel(next, now time.Time) string {
+	if next.IsZero() || !next.After(now) {
+		return ""
+	}
+	minutes := int(next.Sub(now).Minutes())
+	switch {
+	case minutes < 1:
+		return "in <1m"
+	case minutes < 60:
+		return fmt.Sprintf("in %dm", minutes)
+	case minutes < 24*60:
+		return fmt.Sprintf("in %dh %dm", minutes/60, minutes%60)
+	default:
+		return fmt.Sprintf("in %dd %dh", minutes/(24*60), minutes%(24*60)/60)
+	}
+}
+
+func lastRunLabel(entry scheduler.LogEntry, ran bool, now time.Time) string {
+	if !ran {
+		return "never run"
+	}
+	when := scheduler.RelativeLabel(entry.RanAt, now)
+	switch entry.Status {
+	case "success":
+		return fmt.Sprintf("✓ %s", when)
+	case "skipped", "paused":
+		return fmt.Sprintf("– skipped %s", when)
+	case "running":
+		return fmt.Sprintf("● running since %s", when)
+	default:
+		return fmt.Sprintf("✗ failed %s", when)
+	}
+}
+
 func formatRunMessage(entry scheduler.LogEntry) string {
+	return runStatusMessage(entry) + usageSuffix(entry)
+}
+
+// usageSuffix is empty when the run recorded no usage (text output).
+func usageSuffix(entry scheduler.LogEntry) string {
+	var b strings.Builder
+	if tokens := entry.InputTokens + entry.OutputTokens; tokens > 0 {
+		b.WriteString(" · " + formatTokens(tokens) + " tok")
+	}
+	if entry.CostUSD > 0 {
+		fmt.Fprintf(&b, " · $%.2f", entry.CostUSD)
+	}
+	return b.String()
+}
+
+func formatTokens(n int) string {
+	switch {
+	case n < 1000:
+		return fmt.Sprintf("%d", n)
+	case n < 10000:
+		return fmt.Sprintf("%.1fk", float64(n)/1000)
+	case n < 1000000:
+		return fmt.Sprintf("%dk", n/1000)
+	default:
+		return fmt.Sprintf("%.1fM", float64(n)/1000000)
+	}
+}
+
+func runStatusMessage(entry scheduler.LogEntry) string {
 	status := strings.ToUpper(entry.Status)
 	if status == "" {
 		status = "UNKNOWN"
@@ -2433,6 +5602,24 @@ func formatRunMessage(entry scheduler.LogEntry) string {
 	if entry.Status == "success" {
 		return "OK"
 	}
+	if entry.Status == "running" {
+		return "RUNNING"
+	}
+	if entry.Status == "locked" {
+		return "KEYCHAIN LOCKED"
+	}
+	if entry.Status == "unknown" {
+		return "UNKNOWN: no result recorded"
+	}
+	if entry.Status == "paused" {
+		return "PAUSED: skipped"
+	}
+	if entry.Status == "cancelled" {
+		return "CANCELLED"
+	}
+	if entry.Status == "skipped" {
+		return fmt.Sprintf("SKIPPED: %s", truncateString(entry.Error, 60))
+	}
 	if entry.Error != "" {
 		return fmt.Sprintf("ERROR: %s", truncateString(entry.Error, 60))
 	}
@@ -2501,11 +5688,24 @@ func validateOnceSchedule(date, clock, tz string) error {
 		return fmt.Errorf("invalid date/time")
 	}
 	if !parsed.After(time.Now().In(loc)) {
-		return fmt.Errorf("time is in the past")
+		return errTimeInPast
 	}
 	return nil
 }
 
+// dateInPast reports whether date is before today in tz, so a one-time
+// schedule can be turned back before its time is asked for.
+func dateInPast(date, tz string) bool {
+	loc := time.Local
+	if tz != "" {
+		if l, err := time.LoadLocation(tz); err == nil {
+			loc = l
+		}
+	}
+	// YYYY-MM-DD compares correctly as a string.
+	return strings.TrimSpace(date) < time.Now().In(loc).Format("2006-01-02")
+}
+
 func renderWidth(width int) int {
 	width = safeWidth(width)
 	if width <= 1 {
@@ -2668,12 +5868,21 @@ func isTimeDigits(value string) bool {
 	return true
 }
 
+func (m model) allowsMultipleTimes() bool {
+	return m.schedule.Type == "daily" || m.schedule.Type == "weekly"
+}
+
 func (m *model) findProject(path string) app.Project {
 	for _, project := range m.projects {
 		if project.Path == path {
 			return project
 		}
 	}
+	for _, project := range m.projects {
+		if project.CWD != "" && app.SamePath(project.CWD, path) {
+			return project
+		}
+	}
 	return app.Project{}
 }
 
@@ -2698,6 +5907,24 @@ func (m *model) logProjectPath(entry scheduler.LogEntry) string {
 	return ""
 }
 
+func (m model) expandedLogError() string {
+	if m.stage != stageLogs || !m.logErrorExpanded || len(m.items) == 0 {
+		return ""
+	}
+	item := m.items[m.cursor]
+	if item.kind != itemLog || item.index < 0 || item.index >= len(m.logs) {
+		return ""
+	}
+	return strings.TrimSpace(m.logs[item.index].Error)
+}
+
+func (m model) logScheduleLabel() string {
+	if entry, ok := m.findSchedule(m.logScheduleID); ok {
+		return scheduler.ScheduleLabel(entry)
+	}
+	return m.logScheduleID
+}
+
 func (m *model) logDetailEntry() (scheduler.LogEntry, bool) {
 	if m.logDetailIndex < 0 || m.logDetailIndex >= len(m.logs) {
 		return scheduler.LogEntry{}, false
@@ -2706,11 +5933,16 @@ func (m *model) logDetailEntry() (scheduler.LogEntry, bool) {
 }
 
 func (m *model) findModel(value string) app.ModelOption {
+	value, _ = scheduler.NormalizeModel(value)
 	for _, option := range m.models {
 		if option.Value == value {
 			return option
 		}
 	}
+	if value != "auto" {
+		// Keep a custom model instead of resetting it on edit.
+		return app.ModelOption{Value: value, Label: value}
+	}
 	if len(m.models) > 0 {
 		return m.models[0]
 	}
@@ -2727,10 +5959,12 @@ var asciiArtLines = []string{
 }
 
 const (
-	clearLine   = "\x1b[0K"
-	searchLabel = "Search: "
-	colorReset  = "\x1b[0m"
-	colorRed    = "\x1b[31m"
+	clearLine          = "\x1b[0K"
+	searchLabel        = "Search: "
+	logErrorIndent     = 4
+	scheduleDetailRuns = 5
+	colorReset         = "\x1b[0m"
+	colorRed           = "\x1b[31m"
 )
 
 func clamp(value, minVal, maxVal int) int {